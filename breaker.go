@@ -0,0 +1,66 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrCircuitOpen is returned without sending the request when the circuit breaker rejects it.
+// It wraps the error of the circuit breaker, e.g. gobreaker.ErrOpenState.
+var ErrCircuitOpen = fmt.Errorf("duffel: circuit breaker open")
+
+// CircuitBreaker guards requests to the API, failing them fast while the API is failing consistently.
+// It is satisfied by *gobreaker.CircuitBreaker from github.com/sony/gobreaker.
+type CircuitBreaker interface {
+	// Execute calls req unless the circuit is open, recording whether it failed.
+	Execute(req func() (any, error)) (any, error)
+}
+
+// sendGuarded sends the request through the circuit breaker, if any. Only network errors and
+// 5xx responses count as failures: rate limited and rejected requests do not mean the API is down.
+func (c *client[R, T]) sendGuarded(req *http.Request) (*http.Response, error) {
+	if c.options.CircuitBreaker == nil {
+		return c.sendOnce(req)
+	}
+
+	var (
+		sent bool
+		resp *http.Response
+		err  error
+	)
+	_, breakerErr := c.options.CircuitBreaker.Execute(func() (any, error) {
+		sent = true
+		resp, err = c.sendOnce(req)
+		if isAPIFailure(req, err) {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if !sent {
+		return nil, fmt.Errorf("%w: %w", ErrCircuitOpen, breakerErr)
+	}
+	return resp, err
+}
+
+func isAPIFailure(req *http.Request, err error) bool {
+	if err == nil || req.Context().Err() != nil {
+		return false
+	}
+
+	var derr *DuffelError
+	var uerr *url.Error
+	switch {
+	case errors.As(err, &derr):
+		return derr.StatusCode >= 500
+	case errors.As(err, &uerr):
+		return true
+	default:
+		return false
+	}
+}
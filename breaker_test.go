@@ -0,0 +1,66 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var errOpenState = fmt.Errorf("circuit breaker is open")
+
+// consecutiveBreaker opens after a number of consecutive failures, like gobreaker with ReadyToTrip.
+type consecutiveBreaker struct {
+	maxFailures int
+	failures    int
+}
+
+func (b *consecutiveBreaker) Execute(req func() (any, error)) (any, error) {
+	if b.failures >= b.maxFailures {
+		return nil, errOpenState
+	}
+	result, err := req()
+	if err != nil {
+		b.failures++
+	} else {
+		b.failures = 0
+	}
+	return result, err
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(422).
+		File("fixtures/422-validation-error.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Times(2).
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+
+	breaker := &consecutiveBreaker{maxFailures: 2}
+	client := New("duffel_test_123", WithCircuitBreaker(breaker), WithRetries(3, time.Second), WithClock(newFakeClock()))
+
+	// A validation error does not count as a failure and is not retried.
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.True(IsErrorType(err, ValidationError))
+	a.Equal(0, breaker.failures)
+
+	// Two 503 responses open the circuit, failing the third attempt without sending it.
+	_, err = client.GetOrder(context.TODO(), "ord_123")
+	a.ErrorIs(err, ErrCircuitOpen)
+	a.ErrorIs(err, errOpenState)
+	a.Equal(2, breaker.failures)
+	a.True(gock.IsDone())
+}
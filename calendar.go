@@ -0,0 +1,56 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarEvent describes a flight of an order as a calendar entry.
+// Serialising it (e.g. to iCalendar) is left to the caller.
+type CalendarEvent struct {
+	// UID is the segment ID, stable across updates of the order.
+	UID string
+	// Summary is the marketing flight number and route, e.g. "BA1234 LHR→JFK".
+	Summary string
+	// Location is the departure airport, e.g. "Heathrow (LHR)".
+	Location string
+	// Start and End are the departure and arrival times in the airports' time zones.
+	Start time.Time
+	End   time.Time
+}
+
+// CalendarEvents returns one calendar event per segment of the order, in itinerary order.
+// It fails if a segment time cannot be resolved in its airport's time zone.
+func (o *Order) CalendarEvents() ([]CalendarEvent, error) {
+	events := make([]CalendarEvent, 0)
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			start, err := segment.DepartingAt()
+			if err != nil {
+				return nil, fmt.Errorf("segment %s: %w", segment.ID, err)
+			}
+			end, err := segment.ArrivingAt()
+			if err != nil {
+				return nil, fmt.Errorf("segment %s: %w", segment.ID, err)
+			}
+
+			events = append(
+				events, CalendarEvent{
+					UID: segment.ID,
+					Summary: fmt.Sprintf(
+						"%s%s %s→%s", segment.MarketingCarrier.IATACode, segment.MarketingCarrierFlightNumber,
+						segment.Origin.IATACode, segment.Destination.IATACode,
+					),
+					Location: fmt.Sprintf("%s (%s)", segment.Origin.Name, segment.Origin.IATACode),
+					Start:    start,
+					End:      end,
+				},
+			)
+		}
+	}
+	return events, nil
+}
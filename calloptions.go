@@ -0,0 +1,138 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	callOptionsKey  struct{}
+	callTimeoutKey  struct{}
+	callPriorityKey struct{}
+	callThrottleKey struct{}
+)
+
+// Priority ranks requests competing for the rate limit quota when throttling with WithRateLimitThrottling.
+type Priority int
+
+const (
+	// PriorityBackground is for requests that can wait, such as syncing orders. They leave the quota
+	// reserved with WithBackgroundReserve to other requests.
+	PriorityBackground Priority = iota - 1
+	// PriorityNormal is the priority of requests made without WithPriority.
+	PriorityNormal
+	// PriorityInteractive is for requests a user is waiting for, such as searches. They are not paced,
+	// and only wait once the quota is exhausted.
+	PriorityInteractive
+)
+
+// WithCallOptions returns a context that applies the given request options to every request made with it,
+// after the client's own. Use it to override the client configuration for a single call:
+//
+//	ctx = duffel.WithCallOptions(ctx, duffel.WithCallAPIVersion("v2"))
+//	offer, err := client.GetOffer(ctx, id)
+func WithCallOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	existing := callOptionsFromContext(ctx)
+	combined := make([]RequestOption, 0, len(existing)+len(opts))
+	combined = append(combined, existing...)
+	combined = append(combined, opts...)
+	return context.WithValue(ctx, callOptionsKey{}, combined)
+}
+
+func callOptionsFromContext(ctx context.Context) []RequestOption {
+	opts, _ := ctx.Value(callOptionsKey{}).([]RequestOption)
+	return opts
+}
+
+// WithCallTimeout returns a context that bounds every request made with it by the given timeout
+// instead of the client's, e.g. to give offer requests a larger budget than quick lookups.
+// The timeout applies to each request, so every page of a list gets its own. The deadline of ctx
+// still applies when it is earlier.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+func callTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
+
+// WithPriority returns a context that gives the requests made with it the given priority.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, callPriorityKey{}, priority)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	priority, _ := ctx.Value(callPriorityKey{}).(Priority)
+	return priority
+}
+
+// withThrottle returns a context that paces the requests made with it with t, when the client does not
+// throttle them itself, e.g. so that the requests of a batch share the rate limit quota.
+func withThrottle(ctx context.Context, t *throttle) context.Context {
+	return context.WithValue(ctx, callThrottleKey{}, t)
+}
+
+func throttleFromContext(ctx context.Context) *throttle {
+	t, _ := ctx.Value(callThrottleKey{}).(*throttle)
+	return t
+}
+
+// WithCallAPIVersion overrides the "Duffel-Version" header of the client for a single call,
+// e.g. to try one endpoint against a newer API version.
+func WithCallAPIVersion(version string) RequestOption {
+	return func(req *http.Request) error {
+		req.Header.Set(APIVersionHeader, version)
+		return nil
+	}
+}
+
+// IdempotencyKeyHeader is the header Duffel uses to deduplicate retried requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey sets the "Idempotency-Key" header of a single call, so that retrying it with
+// the same key does not repeat its effect, e.g. vaulting a card twice.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(req *http.Request) error {
+		req.Header.Set(IdempotencyKeyHeader, key)
+		return nil
+	}
+}
+
+// withGeneratedIdempotencyKey sets a random "Idempotency-Key" header, generated once per call so that
+// automatic retries of the call share it. A key given with WithIdempotencyKey takes precedence.
+func withGeneratedIdempotencyKey() RequestOption {
+	var key string
+	return func(req *http.Request) error {
+		if req.Header.Get(IdempotencyKeyHeader) != "" {
+			return nil
+		}
+		if key == "" {
+			var err error
+			key, err = newIdempotencyKey()
+			if err != nil {
+				return err
+			}
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+		return nil
+	}
+}
+
+// newIdempotencyKey returns a random version 4 UUID.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("duffel: failed to generate idempotency key: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// RecordOnce replays the cassette if its file exists, and otherwise records real interactions to it.
+	RecordOnce RecordMode = iota
+	// RecordNever only replays the cassette, failing requests that were not recorded.
+	RecordNever
+	// RecordAlways records real interactions, replacing the cassette.
+	RecordAlways
+)
+
+// recordedHeaders lists the response headers kept in a cassette, as read by the client on replay.
+var recordedHeaders = []string{
+	"Content-Type",
+	"Date",
+	"Ratelimit-Limit",
+	"Ratelimit-Remaining",
+	"Ratelimit-Reset",
+	"Retry-After",
+	APIVersionHeader,
+	RequestIDHeader,
+}
+
+type (
+	// RecordMode controls whether a cassette records real interactions or replays recorded ones.
+	RecordMode int
+
+	cassette struct {
+		Interactions []*interaction `json:"interactions"`
+	}
+
+	interaction struct {
+		Request  recordedRequest  `json:"request"`
+		Response recordedResponse `json:"response"`
+		used     bool
+	}
+
+	// recordedRequest holds what requests are matched on. Headers, including the API token, are not kept,
+	// and sensitive data is redacted from the body.
+	recordedRequest struct {
+		Method string `json:"method"`
+		URI    string `json:"uri"`
+		Body   string `json:"body,omitempty"`
+	}
+
+	recordedResponse struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	}
+
+	// cassetteTransport records interactions with the Duffel API to a file and replays them,
+	// matching requests on method, path with query, and body with its JSON normalised.
+	cassetteTransport struct {
+		path string
+		mode RecordMode
+		base http.RoundTripper
+
+		mu        sync.Mutex
+		loaded    bool
+		recording bool
+		cassette  cassette
+	}
+)
+
+// wrapClient returns a copy of client whose requests go through the cassette.
+func (t *cassetteTransport) wrapClient(client *http.Client) *http.Client {
+	wrapped := *client
+	t.base = client.Transport
+	if t.base == nil {
+		t.base = http.DefaultTransport
+	}
+	wrapped.Transport = t
+	return &wrapped
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+
+	if !t.recording {
+		for _, i := range t.cassette.Interactions {
+			if !i.used && i.Request == recorded {
+				i.used = true
+				return i.Response.toResponse(req), nil
+			}
+		}
+		return nil, fmt.Errorf("duffel: no recorded interaction for %s %s in %s", req.Method, recorded.URI, t.path)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := newRecordedResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	t.cassette.Interactions = append(t.cassette.Interactions, &interaction{Request: recorded, Response: response})
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	return response.toResponse(req), nil
+}
+
+// load reads the cassette on first use and decides whether to record or replay.
+func (t *cassetteTransport) load() error {
+	if t.loaded {
+		return nil
+	}
+
+	if t.mode == RecordAlways {
+		t.recording = true
+		t.loaded = true
+		return nil
+	}
+
+	b, err := os.ReadFile(t.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && t.mode == RecordOnce:
+		t.recording = true
+	case err != nil:
+		return fmt.Errorf("duffel: failed to read cassette: %w", err)
+	default:
+		if err := json.Unmarshal(b, &t.cassette); err != nil {
+			return fmt.Errorf("duffel: failed to decode cassette %s: %w", t.path, err)
+		}
+	}
+	t.loaded = true
+	return nil
+}
+
+func (t *cassetteTransport) save() error {
+	b, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, b, 0o644)
+}
+
+func newRecordedRequest(req *http.Request) (recordedRequest, error) {
+	recorded := recordedRequest{Method: req.Method, URI: req.URL.RequestURI()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return recordedRequest{}, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	recorded.Body = redactBody(normaliseBody(b))
+	return recorded, nil
+}
+
+// normaliseBody re-encodes a JSON body so that formatting and key order do not affect matching.
+func normaliseBody(b []byte) string {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	normalised, err := json.Marshal(v)
+	if err != nil {
+		return string(b)
+	}
+	return string(normalised)
+}
+
+// newRecordedResponse reads the response, decompressing it so that the cassette stays readable.
+// Only the headers in recordedHeaders are kept, and sensitive data is redacted from the body.
+func newRecordedResponse(resp *http.Response) (recordedResponse, error) {
+	defer resp.Body.Close()
+
+	reader, err := gzipResponseReader(resp)
+	if err != nil {
+		return recordedResponse{}, err
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return recordedResponse{}, err
+	}
+
+	header := make(http.Header, len(recordedHeaders))
+	for _, name := range recordedHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return recordedResponse{StatusCode: resp.StatusCode, Header: header, Body: redactBody(string(b))}, nil
+}
+
+func (r recordedResponse) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(r.Body))),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
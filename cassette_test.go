@@ -0,0 +1,113 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWithCassette(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.TODO()
+	input := OfferRequestInput{
+		Slices: []OfferRequestSlice{{Origin: "JFK", Destination: "AUS"}},
+	}
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	recorder := New("duffel_test_secret", WithCassette(path, RecordOnce))
+	recorded, err := recorder.CreateOfferRequest(ctx, input)
+	a.NoError(err)
+	a.True(gock.IsDone())
+	gock.Off()
+
+	b, err := os.ReadFile(path)
+	a.NoError(err)
+	a.NotContains(string(b), "duffel_test_secret")
+
+	player := New("duffel_test_other", WithCassette(path, RecordNever))
+	replayed, err := player.CreateOfferRequest(ctx, input)
+	if a.NoError(err) {
+		a.Equal(recorded.ID, replayed.ID)
+	}
+
+	// Each interaction is replayed once, and other requests were not recorded.
+	_, err = player.CreateOfferRequest(ctx, input)
+	a.ErrorContains(err, "no recorded interaction")
+	_, err = New("duffel_test_other", WithCassette(path, RecordNever)).GetOrder(ctx, "ord_123")
+	a.ErrorContains(err, "no recorded interaction for GET /air/orders/ord_123")
+}
+
+func TestWithCassetteMissingFile(t *testing.T) {
+	a := assert.New(t)
+
+	client := New("duffel_test_123", WithCassette(filepath.Join(t.TempDir(), "missing.json"), RecordNever))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.ErrorContains(err, "failed to read cassette")
+}
+
+func TestWithCassetteRedactsSensitiveData(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.TODO()
+	input := CreateOrderInput{
+		Type:           OrderTypeInstant,
+		SelectedOffers: []string{"off_00009htYpSCXrwaB9DnUm0"},
+		Passengers: []OrderPassenger{
+			{
+				ID:         "pas_00009hj8USM7Ncg31cBCLL",
+				GivenName:  "Amelia",
+				FamilyName: "Earhart",
+				BornOn:     Date(time.Date(1987, 7, 24, 0, 0, 0, 0, time.UTC)),
+				Email:      "amelia@duffel.com",
+			},
+		},
+	}
+
+	gock.New("https://api.duffel.com").
+		Post("/air/orders").
+		Reply(201).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		SetHeader("Set-Cookie", "session=secret").
+		File("fixtures/201-create-order.json")
+
+	recorder := New("duffel_test_secret", WithCassette(path, RecordOnce))
+	_, err := recorder.CreateOrder(ctx, input)
+	a.NoError(err)
+	a.True(gock.IsDone())
+	gock.Off()
+
+	b, err := os.ReadFile(path)
+	a.NoError(err)
+	for _, secret := range []string{"Amelia", "Earhart", "1987-07-24", "amelia@duffel.com", "session=secret"} {
+		a.NotContains(string(b), secret)
+	}
+	a.Contains(string(b), "Ratelimit-Limit")
+
+	// The incoming request is redacted the same way, so it still matches the recorded one.
+	player := New("duffel_test_other", WithCassette(path, RecordNever))
+	replayed, err := player.CreateOrder(ctx, input)
+	if a.NoError(err) {
+		a.Equal("REDACTED", replayed.Passengers[0].GivenName)
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/segmentio/encoding/json"
@@ -20,6 +22,13 @@ import (
 
 const RequestIDHeader = "x-request-id"
 
+// APIVersionHeader is the header carrying the Duffel API version of requests and responses.
+const APIVersionHeader = "Duffel-Version"
+
+// ErrAPIVersionMismatch is returned in strict version mode when the API responds with another
+// version than the one requested.
+var ErrAPIVersionMismatch = fmt.Errorf("duffel: API version mismatch")
+
 type Payload[T any] struct {
 	Data T `json:"data"`
 }
@@ -31,6 +40,17 @@ type ResponsePayload[T any] struct {
 
 type RequestOption func(req *http.Request) error
 
+// RoundTripFunc sends a request and returns its response, like http.RoundTripper, which it implements.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every request, e.g. to rewrite headers, log, or inject faults.
+// It is called for every attempt of a retried request.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 type EmptyPayload struct{}
 
 func buildRequestPayload[T any](data T) *Payload[T] {
@@ -52,9 +72,17 @@ func encodePayload[T any](requestInput T) (io.ReadCloser, error) {
 func (c *client[R, T]) makeRequest(
 	ctx context.Context, resourceName string, method string, body io.ReadCloser, opts ...RequestOption,
 ) (*http.Response, error) {
-	if c.APIToken == "" {
+	token, err := resolveToken(ctx, c.APIToken, c.options)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
 		return nil, fmt.Errorf("duffel: missing API token")
 	}
+	c.throttle = c.throttleFor(token)
+	if c.throttle == nil {
+		c.throttle = throttleFromContext(ctx)
+	}
 
 	u, err := c.buildRequestURL(resourceName)
 	if err != nil {
@@ -67,20 +95,28 @@ func (c *client[R, T]) makeRequest(
 	}
 
 	if method != http.MethodGet {
-		req.Body = body
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(b)), nil
+		}
 	}
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
-	if !c.options.Debug {
+	if !c.options.debug.Load() {
 		req.Header.Add("Accept-Encoding", "gzip")
 	}
 	req.Header.Add("User-Agent", c.options.UserAgent)
-	req.Header.Add("Duffel-Version", c.options.Version)
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.APIToken))
+	req.Header.Add(SDKHeader, sdkHeaderValue())
+	req.Header.Add(APIVersionHeader, c.options.Version)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	// Apply request options
-	for _, o := range opts {
+	// Apply request options, then the call options of the context
+	for _, o := range slices.Concat(opts, callOptionsFromContext(ctx)) {
 		if o != nil {
 			err := o(req)
 			if err != nil {
@@ -89,42 +125,139 @@ func (c *client[R, T]) makeRequest(
 		}
 	}
 
-	if c.options.Debug {
+	if c.options.RequestCapture != nil {
+		err := captureRequest(req, c.options.RequestCapture)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.options.debug.Load() {
 		b, err := httputil.DumpRequestOut(req, true)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Printf("REQUEST:\n%s\n", string(b))
+		fmt.Fprintf(c.options.debugWriter(), "REQUEST:\n%s\n", redactDump(b))
+	}
+
+	return c.send(req)
+}
+
+// send sends the request, retrying it as long as the retry policy allows.
+func (c *client[R, T]) send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.wait(req); err != nil {
+			return nil, err
+		}
+
+		start := c.options.Clock.Now()
+		resp, err := c.sendGuarded(req)
+		c.logAttempt(req, attempt, c.options.Clock.Now().Sub(start), resp, err)
+
+		delay, retry := c.options.Retry.next(req, attempt, err)
+		if !retry {
+			return resp, err
+		}
+
+		c.log(req.Context(), slog.LevelInfo, "duffel: retrying request",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+		)
+		if err := c.options.Clock.Sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
+}
 
-	resp, err := c.httpDoer.Do(req)
+func (c *client[R, T]) sendOnce(req *http.Request) (*http.Response, error) {
+	resp, err := c.roundTrip()(req)
 	if err != nil {
 		return nil, err
 	}
+	recordResponseMeta(req.Context(), resp)
 
-	if c.options.Debug {
+	if c.options.debug.Load() {
 		b, err := httputil.DumpResponse(resp, true)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Printf("RESPONSE:\n%s\n", string(b))
+		fmt.Fprintf(c.options.debugWriter(), "RESPONSE:\n%s\n", redactDump(b))
 	}
 
 	if resp.StatusCode > 399 {
-		err = decodeError(resp)
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+
+	if err := c.checkAPIVersion(req, resp); err != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, err
+	}
+
+	for _, afterResponse := range c.afterResponse {
+		afterResponse(resp)
+	}
+	rateLimit, err := parseRateLimit(resp)
+	if err != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, err
+	}
+	if err := c.updateRateLimit(resp, rateLimit); err != nil {
 		return nil, err
 	}
 
 	return resp, nil
 }
 
+// roundTrip returns the HTTP client wrapped in the middleware, the first registered being the outermost.
+func (c *client[R, T]) roundTrip() RoundTripFunc {
+	rt := RoundTripFunc(c.httpDoer.Do)
+	for i := len(c.options.Middleware) - 1; i >= 0; i-- {
+		rt = c.options.Middleware[i](rt)
+	}
+	return rt
+}
+
+// checkAPIVersion compares the version requested with the version the API responded with, if any,
+// reporting a mismatch to the drift handler and, in strict mode, failing the request.
+func (c *client[R, T]) checkAPIVersion(req *http.Request, resp *http.Response) error {
+	sent, received := req.Header.Get(APIVersionHeader), resp.Header.Get(APIVersionHeader)
+	if received == "" || received == sent {
+		return nil
+	}
+
+	c.log(req.Context(), slog.LevelWarn, "duffel: API version mismatch",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("requested", sent),
+		slog.String("received", received),
+	)
+	if c.options.OnVersionDrift != nil {
+		c.options.OnVersionDrift(req.Method, req.URL.Path, sent, received)
+	}
+	if c.options.StrictVersion {
+		return fmt.Errorf("%w: requested %s, received %s", ErrAPIVersionMismatch, sent, received)
+	}
+	return nil
+}
+
 func (c *client[R, T]) buildRequestURL(resourceName string) (*url.URL, error) {
 	u, err := url.Parse(c.options.Host)
 
 	if err != nil {
 		return nil, err
 	}
-	u.Path = resourceName
+	u.Path = strings.TrimSuffix(u.Path, "/") + resourceName
 
 	return u, nil
 }
@@ -132,7 +265,7 @@ func (c *client[R, T]) buildRequestURL(resourceName string) (*url.URL, error) {
 func gzipResponseReader(response *http.Response) (io.ReadCloser, error) {
 	var reader io.ReadCloser
 	var err error
-	if response.Header.Get("Content-Encoding") == "gzip" {
+	if strings.EqualFold(strings.TrimSpace(response.Header.Get("Content-Encoding")), "gzip") {
 		reader, err = gzip.NewReader(response.Body)
 		if err != nil {
 			return nil, err
@@ -143,6 +276,28 @@ func gzipResponseReader(response *http.Response) (io.ReadCloser, error) {
 	return reader, nil
 }
 
+// captureRequest passes a copy of the request to capture, leaving the request body readable.
+func captureRequest(req *http.Request, capture RequestCaptureFunc) error {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		body = b
+	}
+
+	headers := req.Header.Clone()
+	if headers.Get("Authorization") != "" {
+		headers.Set("Authorization", "Bearer REDACTED")
+	}
+
+	capture(req.Method, req.URL.String(), headers, body)
+	return nil
+}
+
 func decodeError(response *http.Response) error {
 	reader, err := gzipResponseReader(response)
 	if err != nil {
@@ -153,11 +308,26 @@ func decodeError(response *http.Response) error {
 
 	retryable := response.StatusCode == http.StatusServiceUnavailable ||
 		response.StatusCode == http.StatusGatewayTimeout
+	retryAfter, _ := parseRetryAfter(response.Header.Get("Retry-After"), responseDate(response))
+
+	var rateLimit *RateLimit
+	if response.StatusCode == http.StatusTooManyRequests {
+		retryable = true
+		// The rate limit headers may be missing, e.g. when the limit is enforced by a proxy.
+		if rl, err := parseRateLimit(response); err == nil {
+			rateLimit = rl
+			if retryAfter == 0 {
+				retryAfter = max(rl.Period, 0)
+			}
+		}
+	}
 
 	if strings.HasPrefix(contentType, "text/html") {
 		return &DuffelError{
 			StatusCode: response.StatusCode,
 			Retryable:  retryable,
+			RetryAfter: retryAfter,
+			RateLimit:  rateLimit,
 			Errors: []Error{
 				{
 					Type:    ApiError,
@@ -172,6 +342,8 @@ func decodeError(response *http.Response) error {
 	derr := &DuffelError{
 		StatusCode: response.StatusCode,
 		Retryable:  retryable,
+		RetryAfter: retryAfter,
+		RateLimit:  rateLimit,
 	}
 	err = json.NewDecoder(reader).Decode(derr)
 	if err != nil {
@@ -5,8 +5,15 @@
 package duffel
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -125,6 +132,39 @@ func TestClientErrorWithSource(t *testing.T) {
 	a.NotNil(derr.Errors[0].Source)
 	a.Equal("origin", derr.Errors[0].Source.Field)
 	a.Equal("/slices/0/origin", derr.Errors[0].Source.Pointer)
+	a.Equal(http.StatusUnprocessableEntity, derr.StatusCode)
+	a.Equal(
+		[]FieldError{
+			{
+				Field:   "origin",
+				Pointer: "/slices/0/origin",
+				Code:    ValidationRequired,
+				Message: "Field 'origin' can't be blank",
+			},
+		}, derr.ValidationErrors(),
+	)
+}
+
+func TestValidationErrorsFiltersType(t *testing.T) {
+	a := assert.New(t)
+
+	var missing *DuffelError
+	a.Nil(missing.ValidationErrors())
+
+	derr := &DuffelError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors: []Error{
+			{Type: InvalidRequestError, Code: "unsupported_version", Message: "Unsupported version"},
+			{Type: ValidationError, Code: ValidationRequired, Message: "Field 'origin' can't be blank"},
+		},
+	}
+	a.Equal(
+		[]FieldError{{Code: ValidationRequired, Message: "Field 'origin' can't be blank"}},
+		derr.ValidationErrors(),
+	)
+
+	derr.StatusCode = http.StatusBadRequest
+	a.Nil(derr.ValidationErrors())
 }
 
 func TestRateLimitPreemptionReturnsDuffelError(t *testing.T) {
@@ -151,6 +191,75 @@ func TestRateLimitPreemptionReturnsDuffelError(t *testing.T) {
 	derr, ok := err.(*DuffelError)
 	a.True(ok)
 	a.Equal(http.StatusTooManyRequests, derr.StatusCode)
+	if a.NotNil(derr.RateLimit) {
+		a.Equal(5, derr.RateLimit.Limit)
+	}
+}
+
+func TestRateLimitPreemptionPrefersRetryAfter(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	now := time.Now().UTC()
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", now.Add(30*time.Second).Format(time.RFC1123)).
+		SetHeader("Retry-After", now.Add(90*time.Second).Format(http.TimeFormat)).
+		SetHeader("Date", now.Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	client := New("duffel_test_123")
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+
+	derr, ok := err.(*DuffelError)
+	if a.True(ok) {
+		a.Equal(90*time.Second, derr.RetryAfter)
+	}
+}
+
+func TestClientErrorRetryAfter(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(503).
+		SetHeader("Retry-After", "120").
+		File("fixtures/503-service-unavailable.json")
+
+	client := New("duffel_test_123")
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+
+	derr, ok := err.(*DuffelError)
+	if a.True(ok) {
+		a.True(derr.Retryable)
+		a.Equal(2*time.Minute, derr.RetryAfter)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("30", now)
+	a.True(ok)
+	a.Equal(30*time.Second, delay)
+
+	delay, ok = parseRetryAfter("Wed, 01 May 2024 10:02:00 GMT", now)
+	a.True(ok)
+	a.Equal(2*time.Minute, delay)
+
+	delay, ok = parseRetryAfter("Wed, 01 May 2024 09:00:00 GMT", now)
+	a.True(ok)
+	a.Zero(delay)
+
+	_, ok = parseRetryAfter("", now)
+	a.False(ok)
+	_, ok = parseRetryAfter("soon", now)
+	a.False(ok)
 }
 
 func TestDefaultTimeoutIs130Seconds(t *testing.T) {
@@ -166,3 +275,467 @@ func TestCustomTimeout(t *testing.T) {
 	api := client.(*API)
 	a.Equal(60*time.Second, api.options.Timeout)
 }
+
+func TestWithTransportConfig(t *testing.T) {
+	a := assert.New(t)
+	client := New("duffel_test_123", WithTransportConfig(100, 50, 90*time.Second))
+	api := client.(*API)
+
+	transport, ok := api.httpDoer.Transport.(*http.Transport)
+	a.True(ok)
+	a.Equal(100, transport.MaxIdleConns)
+	a.Equal(50, transport.MaxConnsPerHost)
+	a.Equal(50, transport.MaxIdleConnsPerHost)
+	a.Equal(90*time.Second, transport.IdleConnTimeout)
+	a.NotSame(http.DefaultTransport, api.httpDoer.Transport)
+}
+
+func TestWithTransportTuning(t *testing.T) {
+	a := assert.New(t)
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	client := New(
+		"duffel_test_123",
+		WithTLSConfig(tlsConfig),
+		WithTransportConfig(100, 50, 90*time.Second),
+		WithMaxIdleConnsPerHost(20),
+		WithHTTP2(false),
+	)
+	api := client.(*API)
+
+	transport, ok := api.httpDoer.Transport.(*http.Transport)
+	if !a.True(ok) {
+		return
+	}
+	a.Equal(50, transport.MaxConnsPerHost)
+	a.Equal(20, transport.MaxIdleConnsPerHost)
+	a.Equal(uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+	a.False(transport.ForceAttemptHTTP2)
+	a.NotNil(transport.TLSNextProto)
+	a.Empty(transport.TLSNextProto)
+}
+
+func TestWithTransportConfigIgnoredForCustomClient(t *testing.T) {
+	a := assert.New(t)
+	custom := &http.Client{}
+	client := New("duffel_test_123", WithHTTPClient(custom), WithTransportConfig(100, 50, 90*time.Second))
+	api := client.(*API)
+	a.Same(custom, api.httpDoer)
+}
+
+func TestWithHTTPClientNil(t *testing.T) {
+	a := assert.New(t)
+	client := New("duffel_test_123", WithHTTPClient(nil))
+	api := client.(*API)
+	a.Same(http.DefaultClient, api.httpDoer)
+}
+
+func TestRateLimitSnapshot(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	now := time.Now()
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "42").
+		SetHeader("Ratelimit-Reset", now.Add(time.Minute).Format(time.RFC1123)).
+		SetHeader("Date", now.Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	client := New("duffel_test_123")
+	reporter := client.(RateLimitReporter)
+
+	_, _, _, ok := reporter.RateLimitSnapshot()
+	a.False(ok)
+
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+
+	limit, remaining, resetIn, ok := reporter.RateLimitSnapshot()
+	a.True(ok)
+	a.Equal(60, limit)
+	a.Equal(42, remaining)
+	a.InDelta(time.Minute.Seconds(), resetIn.Seconds(), 2)
+}
+
+func TestWithRequestCapture(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		BodyString(`"origin":"JFK"`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	var (
+		method, url string
+		headers     http.Header
+		body        []byte
+	)
+	client := New(
+		"duffel_test_123", WithRequestCapture(
+			func(m, u string, h http.Header, b []byte) {
+				method, url, headers, body = m, u, h, b
+			},
+		),
+	)
+	_, err := client.CreateOfferRequest(
+		context.TODO(), OfferRequestInput{
+			Slices: []OfferRequestSlice{{Origin: "JFK", Destination: "AUS"}},
+		},
+	)
+	a.NoError(err)
+	a.True(gock.IsDone())
+
+	a.Equal(http.MethodPost, method)
+	a.Equal("https://api.duffel.com/air/offer_requests?return_offers=false", url)
+	a.Equal("Bearer REDACTED", headers.Get("Authorization"))
+	a.Contains(string(body), `"origin":"JFK"`)
+}
+
+func TestWithCallAPIVersion(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/arl_1").
+		MatchHeader("Duffel-Version", "^v3$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"data":{"id":"arl_1"}}`)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/arl_2").
+		MatchHeader("Duffel-Version", "^v2$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"data":{"id":"arl_2"}}`)
+
+	client := New("duffel_test_123")
+	_, err := client.GetAirline(WithCallOptions(context.TODO(), WithCallAPIVersion("v3")), "arl_1")
+	a.NoError(err)
+	_, err = client.GetAirline(context.TODO(), "arl_2")
+	a.NoError(err)
+	a.True(gock.IsDone())
+}
+
+func TestRateLimitFromError(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(429).
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", date.Add(40*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		JSON(`{"errors": [{"type": "rate_limit_error", "code": "rate_limit_exceeded"}]}`)
+
+	client := New("duffel_test_123")
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.True(IsErrorType(err, RateLimitError))
+	a.True(ErrIsRetryable(err))
+
+	rateLimit, ok := RateLimitFromError(err)
+	if a.True(ok) {
+		a.Equal(60, rateLimit.Limit)
+		a.Equal(0, rateLimit.Remaining)
+		a.Equal(date.Add(40*time.Second), rateLimit.ResetAt)
+	}
+	a.Equal(40*time.Second, err.(*DuffelError).RetryAfter)
+
+	_, ok = RateLimitFromError(&DuffelError{StatusCode: http.StatusServiceUnavailable})
+	a.False(ok)
+}
+
+func TestRateLimitRetriedAfterReset(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(429).
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", date.Add(40*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		JSON(`{"errors": [{"type": "rate_limit_error", "code": "rate_limit_exceeded"}]}`)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "60").
+		SetHeader("Ratelimit-Reset", date.Add(100*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Add(40*time.Second).Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(2, time.Second), WithClock(clock))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.Equal([]time.Duration{40 * time.Second}, clock.Sleeps())
+}
+
+func TestWithBaseURL(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://proxy.example.com").
+		Get("/duffel/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	client := New("duffel_test_123", WithBaseURL("https://proxy.example.com/duffel/"))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.True(gock.IsDone())
+}
+
+func TestAPIVersionDrift(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Times(2).
+		Reply(200).
+		SetHeader("Duffel-Version", "v3").
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	var drifts []string
+	client := New("duffel_test_123", WithVersionDriftHandler(func(method, path, sent, received string) {
+		drifts = append(drifts, method+" "+path+" "+sent+"->"+received)
+	}))
+	order, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.NotNil(order)
+	a.Equal([]string{"GET /air/orders/ord_123 v2->v3"}, drifts)
+
+	strict := New("duffel_test_123", WithStrictAPIVersion())
+	_, err = strict.GetOrder(context.TODO(), "ord_123")
+	a.ErrorIs(err, ErrAPIVersionMismatch)
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	a := assert.New(t)
+
+	client := New("duffel_test_123", WithHTTPClient(&http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+	}))
+
+	start := time.Now()
+	ctx := WithCallTimeout(context.TODO(), 10*time.Millisecond)
+	_, err := client.GetOrder(ctx, "ord_123")
+	a.ErrorIs(err, context.DeadlineExceeded)
+	a.Less(time.Since(start), 5*time.Second)
+}
+
+func TestWithMiddleware(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		MatchHeader("X-Tenant", "^acme$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				return next(req)
+			}
+		}
+	}
+	tenant := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Tenant", "acme")
+			resp, err := next(req)
+			if err == nil {
+				calls = append(calls, fmt.Sprintf("status %d", resp.StatusCode))
+			}
+			return resp, err
+		}
+	}
+
+	client := New("duffel_test_123", WithMiddleware(trace("outer"), trace("inner")), WithMiddleware(tenant))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.Equal([]string{"outer", "inner", "status 200"}, calls)
+}
+
+func TestWithMiddlewareFaultInjection(t *testing.T) {
+	a := assert.New(t)
+
+	failing := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"errors": [{"type": "api_error"}]}`)),
+				Request:    req,
+			}, nil
+		}
+	}
+
+	client := New("duffel_test_123", WithMiddleware(failing))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.True(ErrIsRetryable(err))
+}
+
+func TestWithDebugWriter(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Times(2).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	var buf strings.Builder
+	client := New("duffel_test_123", WithDebugWriter(&buf))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.Contains(buf.String(), "REQUEST:\nGET /air/orders/ord_123")
+	a.Contains(buf.String(), "Authorization: REDACTED")
+	a.Contains(buf.String(), "RESPONSE:\nHTTP/1.1 200 OK")
+	a.NotContains(buf.String(), "duffel_test_123")
+
+	buf.Reset()
+	client.(Debugger).SetDebug(false)
+	_, err = client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.Empty(buf.String())
+}
+
+func TestWithResponseMeta(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("x-request-id", "FZW0H3HdJwKk5HMAAKxB").
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "4").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+	gock.New("https://api.duffel.com/air/offer_requests").
+		Reply(400).
+		SetHeader("x-request-id", "Fa0Dm1YMvk8H2TcAAAKx").
+		File("fixtures/400-bad-request.json")
+
+	client := New("duffel_test_123")
+
+	ctx, meta := WithResponseMeta(context.TODO())
+	_, err := client.GetOrder(ctx, "ord_123")
+	a.NoError(err)
+	a.Equal("FZW0H3HdJwKk5HMAAKxB", meta.RequestID)
+	a.Equal(http.StatusOK, meta.StatusCode)
+	a.Equal("4", meta.Header.Get("Ratelimit-Remaining"))
+
+	ctx, meta = WithResponseMeta(context.TODO())
+	_, err = client.CreateOfferRequest(ctx, OfferRequestInput{})
+	a.Error(err)
+	a.Equal("Fa0Dm1YMvk8H2TcAAAKx", meta.RequestID)
+	a.Equal(http.StatusBadRequest, meta.StatusCode)
+}
+
+func gzipFile(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write(b)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestGzipResponses(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		MatchHeader("Accept-Encoding", "^gzip$").
+		Reply(200).
+		SetHeader("Content-Encoding", "GZIP").
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		Body(bytes.NewReader(gzipFile(t, "fixtures/200-get-order.json")))
+	gock.New("https://api.duffel.com/air/offer_requests").
+		MatchHeader("Accept-Encoding", "^gzip$").
+		Reply(400).
+		SetHeader("Content-Encoding", "gzip").
+		Body(bytes.NewReader(gzipFile(t, "fixtures/400-bad-request.json")))
+
+	client := New("duffel_test_123")
+	order, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.Equal("ord_00009hthhsUZ8W4LxQgkjo", order.ID)
+
+	_, err = client.CreateOfferRequest(context.TODO(), OfferRequestInput{})
+	a.True(IsErrorCode(err, AirlineUnknown))
+}
+
+func TestUserAgentAndSDKHeader(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		MatchHeader("User-Agent", "^acme-booking/3.1$").
+		MatchHeader(SDKHeader, `^sdk=duffel-go; version=\S+; go=go\S+; os=\w+; arch=\w+$`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	client := New("duffel_test_123", WithUserAgent("acme-booking/3.1"))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.True(gock.IsDone())
+}
@@ -0,0 +1,41 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"os"
+	"sync"
+)
+
+// TokenEnvVar is the environment variable Default reads the API token from.
+const TokenEnvVar = "DUFFEL_TOKEN"
+
+var (
+	defaultMu     sync.Mutex
+	defaultClient Duffel
+)
+
+// Default returns the client shared by the process, created on first use with the token from the
+// DUFFEL_TOKEN environment variable and the default options. Sharing it shares its HTTP connections
+// and the last request ID and rate limit reported by Duffel. If the variable is unset, requests fail
+// with a missing API token error.
+func Default() Duffel {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultClient == nil {
+		defaultClient = New(os.Getenv(TokenEnvVar))
+	}
+	return defaultClient
+}
+
+// SetDefault replaces the client returned by Default, e.g. with a client configured with options or a
+// test double. Setting nil makes Default create a client from the environment again.
+func SetDefault(client Duffel) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultClient = client
+}
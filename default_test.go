@@ -0,0 +1,26 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault(t *testing.T) {
+	a := assert.New(t)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	t.Setenv(TokenEnvVar, "duffel_test_env")
+	SetDefault(nil)
+	client := Default()
+	a.Same(client, Default())
+	a.Equal("duffel_test_env", client.(*API).APIToken)
+
+	custom := New("duffel_test_custom")
+	SetDefault(custom)
+	a.Same(custom, Default())
+}
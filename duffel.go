@@ -5,9 +5,17 @@
 package duffel
 
 import (
+	"crypto/tls"
+	"io"
+	"log/slog"
 	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"golang.org/x/time/rate"
 )
 
@@ -34,6 +42,22 @@ type (
 		LastRequestID() (string, bool)
 	}
 
+	// Debugger is implemented by the client returned by New, to turn its debug output on and off at
+	// runtime. It is kept out of Duffel so that other implementations, such as mocks, need not provide it.
+	Debugger interface {
+		SetDebug(enabled bool)
+	}
+
+	// RateLimitReporter is implemented by the client returned by New, to publish its rate limit as
+	// metrics. It is kept out of Duffel so that other implementations, such as mocks, need not provide it:
+	//
+	//	if reporter, ok := client.(duffel.RateLimitReporter); ok {
+	//		limit, remaining, resetIn, ok := reporter.RateLimitSnapshot()
+	//	}
+	RateLimitReporter interface {
+		RateLimitSnapshot() (limit, remaining int, resetIn time.Duration, ok bool)
+	}
+
 	Gender string
 
 	LocationType string
@@ -80,6 +104,9 @@ type (
 		RawArrivingAt       string   `json:"arriving_at"`
 		Aircraft            Aircraft `json:"aircraft"`
 		Stops               []Stop   `json:"stops"`
+		// Disclosures are the notices to display for the segment, e.g. "Operated by American Airlines"
+		// for a codeshare segment.
+		Disclosures []string `json:"disclosures,omitempty"`
 	}
 
 	Stop struct {
@@ -97,6 +124,40 @@ type (
 		CabinClass              CabinClass `json:"cabin_class"`
 		Baggages                []Baggage  `json:"baggages"`
 		Seat                    Seat       `json:"seat"`
+		// Cabin is nil when the airline does not describe the cabin.
+		Cabin *SegmentCabin `json:"cabin,omitempty"`
+	}
+
+	// SegmentCabin describes the cabin a passenger travels in on a segment.
+	SegmentCabin struct {
+		Name          CabinClass `json:"name"`
+		MarketingName string     `json:"marketing_name"`
+		// Amenities is nil when the airline does not describe them.
+		Amenities *Amenities `json:"amenities,omitempty"`
+	}
+
+	// Amenities of a cabin. Each amenity is nil when its availability is unknown.
+	Amenities struct {
+		WiFi  *WiFiAmenity  `json:"wifi,omitempty"`
+		Power *PowerAmenity `json:"power,omitempty"`
+		Seat  *SeatAmenity  `json:"seat,omitempty"`
+	}
+
+	WiFiAmenity struct {
+		Available bool `json:"available"`
+		// Possible values: "free", "paid", "free or paid" or "n/a"
+		Cost string `json:"cost"`
+	}
+
+	PowerAmenity struct {
+		Available bool `json:"available"`
+	}
+
+	SeatAmenity struct {
+		// Pitch is the seat pitch in inches, e.g. "32", or "n/a".
+		Pitch string `json:"pitch"`
+		// Possible values: "less", "more", "standard" or "n/a"
+		Legroom string `json:"legroom"`
 	}
 
 	Seat struct {
@@ -161,6 +222,11 @@ type (
 		IATACode        string  `json:"iata_code,omitempty" csv:"city_iata_code"`
 	}
 
+	// OrderPassenger is a passenger of an order.
+	//
+	// Duffel does not accept special service requests (SSRs), such as wheelchair assistance, when
+	// creating an order: there is no field for them and unknown fields are ignored. They have to be
+	// requested through Duffel support or directly with the airline, quoting the order's BookingReference.
 	OrderPassenger struct {
 		// ID is id of the passenger, returned when the offer request was created
 		ID string `json:"id"`
@@ -197,6 +263,10 @@ type (
 
 	// The payment details to use to pay for the order.
 	// This key should be omitted when the order’s type is hold.
+	//
+	// Duffel has no reference endpoint listing supported countries or payment currencies, and the
+	// currency is not a free choice: it is the offer's total currency, which depends on the airline
+	// and the Duffel account. Read it from the offer rather than from a static list.
 	PaymentCreateInput struct {
 		// The amount of the payment. This should be the same as the total_amount of the offer specified in selected_offers, plus the total_amount of all the services specified in services.
 		Amount string `json:"amount"`
@@ -234,9 +304,11 @@ type (
 		// The ISO 3166-1 alpha-2 code of the country that issued this identity document
 		IssuingCountryCode string `json:"issuing_country_code"`
 
-		Type string `json:"type"`
+		Type PassengerIdentityDocumentType `json:"type"`
 	}
 
+	PassengerIdentityDocumentType string
+
 	PassengerType string
 
 	PassengerTitle string
@@ -261,6 +333,68 @@ type (
 		HttpDoer  *http.Client
 		Debug     bool
 		Timeout   time.Duration
+		Transport *TransportConfig
+		Clock     Clock
+		// Throttle paces requests according to the rate limit reported by the API.
+		Throttle bool
+		// BackgroundReserve is the quota of each rate limit window that throttled background
+		// requests leave to other requests.
+		BackgroundReserve int
+		// OnVersionDrift is called when the API responds with another version than the one requested.
+		OnVersionDrift VersionDriftFunc
+		// StrictVersion fails requests answered with another API version than the one requested.
+		StrictVersion bool
+		// DebugWriter receives the debug output, os.Stdout by default.
+		DebugWriter io.Writer
+		// Logger receives structured records of requests, responses, retries and rate limits.
+		Logger *slog.Logger
+		// TracerProvider creates the spans of API calls, a no-op provider by default.
+		TracerProvider trace.TracerProvider
+		// TokenProvider resolves the API token of every request, overriding the token of the client.
+		TokenProvider TokenProvider
+		// CircuitBreaker guards every attempt of every request, nil to send them all.
+		CircuitBreaker CircuitBreaker
+		// Middleware wraps the sending of every request, the first being the outermost.
+		Middleware []Middleware
+		// IdempotencyKeys generates an idempotency key for every order, payment and confirmation request.
+		IdempotencyKeys bool
+		// NoOffersError makes CreateOfferRequestWithOffers return ErrNoOffers when no offer is returned.
+		NoOffersError bool
+		// Retry is the policy for retrying failed requests, nil to never retry.
+		Retry *RetryPolicy
+		// RequestCapture is called with every request just before it is sent.
+		RequestCapture RequestCaptureFunc
+
+		cassette *cassetteTransport
+		// debug is the Debug setting, which SetDebug toggles while requests are in flight.
+		debug atomic.Bool
+	}
+
+	// VersionDriftFunc receives the method and path of a request whose response reported
+	// another API version than the one sent.
+	VersionDriftFunc func(method, path, sent, received string)
+
+	// RequestCaptureFunc receives a copy of an outgoing request, with the API token redacted,
+	// e.g. to reconstruct it as a curl command.
+	RequestCaptureFunc func(method, url string, headers http.Header, body []byte)
+
+	// TransportConfig tunes the connection pool of the http.Transport used when no custom
+	// http.Client is supplied with WithHTTPClient. Zero values keep the net/http defaults.
+	TransportConfig struct {
+		// MaxIdleConns is the maximum number of idle connections kept across all hosts.
+		MaxIdleConns int
+		// MaxConnsPerHost limits the total number of connections to the Duffel API host.
+		// It also raises the number of idle connections kept per host, which net/http caps at 2 by default.
+		MaxConnsPerHost int
+		// IdleConnTimeout is how long an idle connection is kept before being closed.
+		IdleConnTimeout time.Duration
+		// MaxIdleConnsPerHost is the number of idle connections kept to the Duffel API host,
+		// overriding the one derived from MaxConnsPerHost.
+		MaxIdleConnsPerHost int
+		// TLSClientConfig is the TLS configuration, e.g. to trust a corporate root CA.
+		TLSClientConfig *tls.Config
+		// DisableHTTP2 restricts connections to HTTP/1.1, which is negotiated otherwise.
+		DisableHTTP2 bool
 	}
 
 	client[Req any, Resp any] struct {
@@ -268,15 +402,28 @@ type (
 		APIToken      string
 		options       *Options
 		limiter       *rate.Limiter
+		throttle      *throttle
+		throttleFor   func(token string) *throttle
 		rateLimit     *RateLimit
 		afterResponse []func(resp *http.Response)
 	}
 
 	API struct {
-		httpDoer      *http.Client
-		APIToken      string
-		options       *Options
+		httpDoer *http.Client
+		APIToken string
+		options  *Options
+
+		// mu guards the state recorded from responses, which may arrive concurrently.
+		mu            sync.RWMutex
 		lastRequestID string
+		lastRateLimit *RateLimit
+		// throttles paces the requests made with each API token, whose rate limits are separate,
+		// when rate limit throttling is enabled.
+		throttles map[string]*throttle
+
+		// loyaltyMu guards loyaltyProgrammes, the loyalty programmes indexed by owner airline IATA code.
+		loyaltyMu         sync.Mutex
+		loyaltyProgrammes map[string]*LoyaltyProgramme
 	}
 )
 
@@ -330,6 +477,11 @@ const (
 
 	LocationTypeAirport LocationType = "airport"
 	LocationTypeCity    LocationType = "city"
+
+	PassengerIdentityDocumentTypePassport               PassengerIdentityDocumentType = "passport"
+	PassengerIdentityDocumentTypeTaxID                  PassengerIdentityDocumentType = "tax_id"
+	PassengerIdentityDocumentTypeKnownTravelerNumber    PassengerIdentityDocumentType = "known_traveler_number"
+	PassengerIdentityDocumentTypePassengerRedressNumber PassengerIdentityDocumentType = "passenger_redress_number"
 )
 
 func New(apiToken string, opts ...Option) Duffel {
@@ -339,25 +491,135 @@ func New(apiToken string, opts ...Option) Duffel {
 		Host:      defaultHost,
 		HttpDoer:  http.DefaultClient,
 		Timeout:   defaultTimeout,
+		Clock:     systemClock{},
+
+		TracerProvider: noop.NewTracerProvider(),
 	}
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	return &API{
+	options.debug.Store(options.Debug)
+
+	if options.Transport != nil && options.HttpDoer == http.DefaultClient {
+		options.HttpDoer = &http.Client{
+			Transport: options.Transport.newTransport(),
+		}
+	}
+
+	if options.cassette != nil {
+		options.HttpDoer = options.cassette.wrapClient(options.HttpDoer)
+	}
+
+	api := &API{
 		httpDoer: options.HttpDoer,
 		APIToken: apiToken,
 		options:  options,
 	}
+	if options.Throttle {
+		api.throttles = make(map[string]*throttle)
+	}
+	return api
+}
+
+// SetDebug turns the debug output of requests and responses on or off, e.g. while reproducing an issue
+// in a running service. See WithDebug.
+func (a *API) SetDebug(enabled bool) {
+	a.options.debug.Store(enabled)
+}
+
+// debugWriter returns the writer of the debug output.
+func (o *Options) debugWriter() io.Writer {
+	if o.DebugWriter == nil {
+		return os.Stdout
+	}
+	return o.DebugWriter
 }
 
 func (a *API) LastRequestID() (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.lastRequestID, a.lastRequestID != ""
 }
 
+func (a *API) setLastRequestID(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastRequestID = id
+}
+
+// RateLimitSnapshot returns the rate limit reported by the most recent response, in a form suitable
+// for publishing as metrics. ok is false until a response with rate limit headers has been received.
+// With per-call tokens, it is the rate limit of the token of that response only.
+func (a *API) RateLimitSnapshot() (limit, remaining int, resetIn time.Duration, ok bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.lastRateLimit == nil {
+		return 0, 0, 0, false
+	}
+
+	resetIn = a.lastRateLimit.ResetAt.Sub(a.options.Clock.Now())
+	if resetIn < 0 {
+		resetIn = 0
+	}
+	return a.lastRateLimit.Limit, a.lastRateLimit.Remaining, resetIn, true
+}
+
+func (a *API) setRateLimit(rateLimit *RateLimit) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastRateLimit = rateLimit
+}
+
+// throttleFor returns the throttle of the requests made with token, nil when throttling is disabled.
+// Throttles of other tokens whose rate limit window has reset are dropped, so that rotated tokens
+// do not accumulate.
+func (a *API) throttleFor(token string) *throttle {
+	if a.throttles == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if t, ok := a.throttles[token]; ok {
+		return t
+	}
+
+	now := a.options.Clock.Now()
+	for other, t := range a.throttles {
+		if t.idle(now) {
+			delete(a.throttles, other)
+		}
+	}
+	t := &throttle{backgroundReserve: a.options.BackgroundReserve}
+	a.throttles[token] = t
+	return t
+}
+
+// batchThrottle returns a throttle for the requests of a batch to share when throttling is disabled,
+// starting from the rate limit reported by the most recent response. It returns nil when throttling
+// is enabled, as throttleFor already paces the requests made with each token.
+func (a *API) batchThrottle() *throttle {
+	if a.throttles != nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	rateLimit := a.lastRateLimit
+	a.mu.RUnlock()
+
+	t := &throttle{}
+	t.seed(a.options.Clock.Now(), rateLimit)
+	return t
+}
+
 // Assert that our interface matches
 var (
-	_ Duffel = (*API)(nil)
+	_ Duffel            = (*API)(nil)
+	_ RateLimitReporter = (*API)(nil)
+	_ Debugger          = (*API)(nil)
 )
 
 func (p PassengerType) String() string {
@@ -368,6 +630,18 @@ func (p PaymentMethod) String() string {
 	return string(p)
 }
 
+// GuessBookingClass returns a guess at the booking class (RBD) of the segment passenger: the first
+// letter of the fare basis code, which airlines conventionally make the booking class. Duffel does not
+// expose the booking class, and fare basis codes do not always follow the convention, so it is only a
+// heuristic and must not be reported as the booked class. It returns an empty string when there is no
+// fare basis code.
+func (p SegmentPassenger) GuessBookingClass() string {
+	if p.FareBasisCode == "" {
+		return ""
+	}
+	return p.FareBasisCode[:1]
+}
+
 func (p CabinClass) String() string {
 	return string(p)
 }
@@ -379,3 +653,7 @@ func (p Gender) String() string {
 func (p PassengerTitle) String() string {
 	return string(p)
 }
+
+func (p PassengerIdentityDocumentType) String() string {
+	return string(p)
+}
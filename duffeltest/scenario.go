@@ -0,0 +1,177 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package duffeltest provides helpers to test code using the duffel client without a Duffel account.
+package duffeltest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thetreep/duffel/v2"
+)
+
+type (
+	// Scenario is an ordered sequence of expected calls to the Duffel API with their stubbed responses,
+	// e.g. offer request, then offers, then order. It fails the test when a call is made out of order,
+	// with an unexpected body, or when expected calls are left over at the end of the test.
+	Scenario struct {
+		t     testing.TB
+		mu    sync.Mutex
+		steps []*Step
+		next  int
+	}
+
+	// Step is an expected call of a Scenario.
+	Step struct {
+		method       string
+		path         string
+		query        map[string]string
+		bodyContains []string
+		status       int
+		body         []byte
+	}
+)
+
+// NewScenario returns an empty scenario, checked for leftover steps when the test ends.
+func NewScenario(t testing.TB) *Scenario {
+	s := &Scenario{t: t}
+	t.Cleanup(s.assertDone)
+	return s
+}
+
+// Expect appends a call with the given method and path, e.g. Expect(http.MethodPost, "/air/offer_requests").
+// It responds 200 with an empty JSON object unless configured otherwise.
+func (s *Scenario) Expect(method, path string) *Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	step := &Step{
+		method: method,
+		path:   path,
+		query:  make(map[string]string),
+		status: http.StatusOK,
+		body:   []byte(`{}`),
+	}
+	s.steps = append(s.steps, step)
+	return step
+}
+
+// WithQuery expects the query parameter key to be set to value.
+func (st *Step) WithQuery(key, value string) *Step {
+	st.query[key] = value
+	return st
+}
+
+// WithBodyContaining expects the request body to contain each of the given strings,
+// e.g. `"selected_offers":["off_123"]`.
+func (st *Step) WithBodyContaining(parts ...string) *Step {
+	st.bodyContains = append(st.bodyContains, parts...)
+	return st
+}
+
+// Respond sets the status and JSON body of the response.
+func (st *Step) Respond(status int, body string) *Step {
+	st.status = status
+	st.body = []byte(body)
+	return st
+}
+
+// RespondWithFile sets the status of the response and reads its JSON body from a file, e.g. a fixture.
+func (st *Step) RespondWithFile(status int, path string) *Step {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("duffeltest: failed to read response file: %v", err))
+	}
+	st.status = status
+	st.body = body
+	return st
+}
+
+// Client returns a duffel client whose requests are served by the scenario.
+func (s *Scenario) Client(opts ...duffel.Option) duffel.Duffel {
+	opts = append(opts, duffel.WithHTTPClient(&http.Client{Transport: s}))
+	return duffel.New("duffel_test_duffeltest", opts...)
+}
+
+// RoundTrip implements http.RoundTripper, serving the request with the next expected step.
+func (s *Scenario) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	if s.next >= len(s.steps) {
+		s.t.Errorf("duffeltest: unexpected call %s %s, all %d steps were done", req.Method, req.URL.Path, len(s.steps))
+		return nil, fmt.Errorf("duffeltest: unexpected call %s %s", req.Method, req.URL.Path)
+	}
+
+	index := s.next
+	step := s.steps[index]
+	s.next++
+
+	if req.Method != step.method || req.URL.Path != step.path {
+		s.t.Errorf(
+			"duffeltest: step %d: expected %s %s, got %s %s", index, step.method, step.path, req.Method, req.URL.Path,
+		)
+		return nil, fmt.Errorf("duffeltest: unexpected call %s %s", req.Method, req.URL.Path)
+	}
+	for key, value := range step.query {
+		if got := req.URL.Query().Get(key); got != value {
+			s.t.Errorf("duffeltest: step %d: expected query %s=%q, got %q", index, key, value, got)
+		}
+	}
+	for _, part := range step.bodyContains {
+		if !bytes.Contains(body, []byte(part)) {
+			s.t.Errorf("duffeltest: step %d: expected body to contain %s, got %s", index, part, body)
+		}
+	}
+
+	now := time.Now().UTC()
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Ratelimit-Limit", "100")
+	header.Set("Ratelimit-Remaining", "100")
+	header.Set("Ratelimit-Reset", now.Add(time.Minute).Format(time.RFC1123))
+	header.Set("Date", now.Format(time.RFC1123))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", step.status, http.StatusText(step.status)),
+		StatusCode:    step.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(step.body)),
+		ContentLength: int64(len(step.body)),
+		Request:       req,
+	}, nil
+}
+
+func (s *Scenario) assertDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next < len(s.steps) {
+		pending := make([]string, 0, len(s.steps)-s.next)
+		for _, step := range s.steps[s.next:] {
+			pending = append(pending, step.method+" "+step.path)
+		}
+		s.t.Errorf("duffeltest: %d steps were not done: %s", len(pending), strings.Join(pending, ", "))
+	}
+}
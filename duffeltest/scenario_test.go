@@ -0,0 +1,93 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffeltest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetreep/duffel/v2"
+)
+
+type recordingT struct {
+	testing.TB
+	errors int
+}
+
+func (r *recordingT) Errorf(string, ...any) { r.errors++ }
+
+func (r *recordingT) Cleanup(func()) {}
+
+func TestScenarioBooking(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.TODO()
+
+	s := NewScenario(t)
+	s.Expect(http.MethodPost, "/air/offer_requests").
+		WithBodyContaining(`"origin":"JFK"`, `"destination":"AUS"`).
+		RespondWithFile(http.StatusOK, "../fixtures/200-get-offer-request.json")
+	s.Expect(http.MethodGet, "/air/offers").
+		WithQuery("offer_request_id", "orq_0000AEtEexyvXbB0OhB5jk").
+		RespondWithFile(http.StatusOK, "../fixtures/200-offers-orq_0000AGqEDX9VCvWmHLBywi.json")
+	s.Expect(http.MethodPost, "/air/orders").
+		WithBodyContaining(`"selected_offers":["off_0000AGqEDmRmLG3liMrI12"]`).
+		RespondWithFile(http.StatusCreated, "../fixtures/201-create-order.json")
+
+	client := s.Client()
+	offerRequest, err := client.CreateOfferRequest(
+		ctx, duffel.OfferRequestInput{
+			Passengers: []duffel.OfferRequestPassenger{{Type: duffel.PassengerTypeAdult}},
+			Slices: []duffel.OfferRequestSlice{
+				{
+					DepartureDate: duffel.Date(time.Now().AddDate(0, 0, 7)),
+					Origin:        "JFK",
+					Destination:   "AUS",
+				},
+			},
+		},
+	)
+	if !a.NoError(err) {
+		return
+	}
+
+	offers := client.ListOffers(ctx, offerRequest.ID)
+	if !a.True(offers.Next()) || !a.NoError(offers.Err()) {
+		return
+	}
+
+	order, err := client.CreateOrder(
+		ctx, duffel.CreateOrderInput{
+			Type:           duffel.OrderTypeInstant,
+			SelectedOffers: []string{offers.Current().ID},
+		},
+	)
+	a.NoError(err)
+	a.NotNil(order)
+}
+
+func TestScenarioUnexpectedCall(t *testing.T) {
+	a := assert.New(t)
+	rt := &recordingT{TB: t}
+
+	s := NewScenario(rt)
+	s.Expect(http.MethodGet, "/air/orders/ord_123")
+
+	_, err := s.Client().GetOrder(context.TODO(), "ord_456")
+	a.Error(err)
+	a.Equal(1, rt.errors)
+}
+
+func TestScenarioLeftoverSteps(t *testing.T) {
+	a := assert.New(t)
+	rt := &recordingT{TB: t}
+
+	s := NewScenario(rt)
+	s.Expect(http.MethodGet, "/air/orders/ord_123")
+	s.assertDone()
+	a.Equal(1, rt.errors)
+}
@@ -4,7 +4,12 @@
 
 package duffel
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
 
 type ErrorType string
 
@@ -219,6 +224,16 @@ func RequestIDFromError(err error) (string, bool) {
 	return "", false
 }
 
+// RateLimitFromError returns the rate limit reported with a 429 Too Many Requests error,
+// to know when requests can be made again.
+func RateLimitFromError(err error) (*RateLimit, bool) {
+	var derr *DuffelError
+	if errors.As(err, &derr) && derr.RateLimit != nil {
+		return derr.RateLimit, true
+	}
+	return nil, false
+}
+
 // ErrIsRetryable returns true if the request that generated this error is retryable.
 func ErrIsRetryable(err error) bool {
 	if err, ok := err.(*DuffelError); ok {
@@ -232,6 +247,13 @@ type DuffelError struct {
 	Errors     []Error   `json:"errors"`
 	StatusCode int       `json:"-"`
 	Retryable  bool      `json:"-"`
+	// RetryAfter is how long to wait before retrying, from the Retry-After header or, for rate limited
+	// requests without it, the rate limit reset. It is zero when the response gave no hint.
+	RetryAfter time.Duration `json:"-"`
+	// RateLimit is the rate limit reported with a 429 Too Many Requests error, nil for other errors.
+	// It is a field of DuffelError rather than a separate error type so that rate limited requests
+	// keep matching IsErrorType(err, RateLimitError) and IsErrorCode(err, RateLimitExceeded).
+	RateLimit *RateLimit `json:"-"`
 }
 
 func (e *DuffelError) Error() string {
@@ -264,6 +286,40 @@ func (e *DuffelError) IsCode(t ErrorCode) bool {
 	return false
 }
 
+// ValidationErrors returns the field-level details of the validation errors, which Duffel
+// returns with a 422 status. Errors without a source have empty Field and Pointer.
+// It returns nil for a nil error or another status, and skips errors of other types.
+func (e *DuffelError) ValidationErrors() []FieldError {
+	if e == nil || e.StatusCode != http.StatusUnprocessableEntity {
+		return nil
+	}
+
+	fields := make([]FieldError, 0)
+	for _, err := range e.Errors {
+		if err.Type != ValidationError {
+			continue
+		}
+
+		field := FieldError{Code: err.Code, Message: err.Message}
+		if err.Source != nil {
+			field.Field = err.Source.Field
+			field.Pointer = err.Source.Pointer
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// FieldError describes why a field of the request failed validation.
+type FieldError struct {
+	// Field is the name of the invalid field, e.g. "origin".
+	Field string
+	// Pointer is the JSON pointer to the invalid field in the request, e.g. "/slices/0/origin".
+	Pointer string
+	Code    ErrorCode
+	Message string
+}
+
 type ErrorSource struct {
 	Field   string `json:"field"`
 	Pointer string `json:"pointer"`
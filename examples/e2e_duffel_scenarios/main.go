@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -21,8 +22,8 @@ func main() {
 		log.Fatal("DUFFEL_TOKEN environment variable not set")
 	}
 
-	client := duffel.New(token)
-	cardsAPIClient := duffel.New(token, duffel.WithDebug(), duffel.WithHost("https://api.duffel.cards/"))
+	client := duffel.New(token, duffel.WithNoOffersError())
+	cardsAPIClient := duffel.New(token, duffel.WithDebug(), duffel.WithBaseURL("https://api.duffel.cards/"))
 	ctx := context.Background()
 
 	t := table.NewWriter()
@@ -73,21 +74,9 @@ func main() {
 }
 
 func createOfferRequest(
-	ctx context.Context, client duffel.Duffel, t table.Writer, testName, origin, destination string,
+	ctx context.Context, client duffel.Duffel, t table.Writer, testName string, scenario duffel.SandboxScenario,
 ) (*duffel.OfferRequest, []*duffel.Offer) {
-	offerReq, err := client.CreateOfferRequest(
-		ctx, duffel.OfferRequestInput{
-			CabinClass: duffel.CabinClassEconomy,
-			Passengers: []duffel.OfferRequestPassenger{{Type: duffel.PassengerTypeAdult}},
-			Slices: []duffel.OfferRequestSlice{
-				{
-					Origin:        origin,
-					Destination:   destination,
-					DepartureDate: duffel.Date(time.Now().AddDate(0, 0, 7)),
-				},
-			},
-		},
-	)
+	offerReq, err := client.CreateOfferRequest(ctx, duffel.SandboxOfferRequestInput(scenario))
 
 	if err != nil {
 		t.AppendRow(
@@ -115,32 +104,42 @@ func createOfferRequest(
 }
 
 func testNoFlights(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "No Flights", "PVD", "RAI")
-	if allOffers != nil && len(allOffers) == 0 {
+	creator := client.(duffel.OfferRequestWithOffersCreator)
+	offerReq, err := creator.CreateOfferRequestWithOffers(ctx, duffel.SandboxOfferRequestInput(duffel.SandboxScenarioNoFlights))
+	switch {
+	case errors.Is(err, duffel.ErrNoOffers):
 		t.AppendRow(
 			table.Row{"No Flights", "Check Offers", "PASSED", "No offers returned as expected"}, rowConfigAutoMerge,
 		)
-	} else if allOffers != nil {
+	case err != nil:
+		t.AppendRow(
+			table.Row{"No Flights", "Create Offer Request", "FAILED", fmt.Sprintf("Error: %v", err)}, rowConfigAutoMerge,
+		)
+	default:
 		t.AppendRow(
 			table.Row{
-				"No Flights", "Check Offers", "FAILED", fmt.Sprintf("Expected 0 offers, got %d", len(allOffers)),
+				"No Flights", "Check Offers", "FAILED", fmt.Sprintf("Expected 0 offers, got %d", len(offerReq.Offers)),
 			}, rowConfigAutoMerge,
 		)
 	}
 }
 
 func testHoldOrder(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Hold Order", "JFK", "EWR")
+	_, allOffers := createOfferRequest(ctx, client, t, "Hold Order", duffel.SandboxScenarioHoldOrder)
 	if allOffers == nil {
 		return
 	}
 
 	holdOrderFound := false
 	for _, offer := range allOffers {
-		if !offer.PaymentRequirements.RequiresInstantPayment {
+		if canHold, until := offer.HoldInfo(); canHold {
 			holdOrderFound = true
+			details := fmt.Sprintf("Offer ID: %s", offer.ID)
+			if until != nil {
+				details += fmt.Sprintf(", Pay by: %s", until.Format(time.RFC3339))
+			}
 			t.AppendRow(
-				table.Row{"Hold Order", "Check Offer", "PASSED", fmt.Sprintf("Offer ID: %s", offer.ID)},
+				table.Row{"Hold Order", "Check Offer", "PASSED", details},
 				rowConfigAutoMerge,
 			)
 			break
@@ -155,19 +154,19 @@ func testHoldOrder(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t
 }
 
 func testConnectingFlights(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Connecting Flights", "LHR", "DXB")
+	_, allOffers := createOfferRequest(ctx, client, t, "Connecting Flights", duffel.SandboxScenarioConnectingFlights)
 	if allOffers == nil {
 		return
 	}
 
 	connectingFlightFound := false
 	for _, offer := range allOffers {
-		if len(offer.Slices[0].Segments) > 1 {
+		if offer.MaxConnections() > 0 {
 			connectingFlightFound = true
 			t.AppendRow(
 				table.Row{
 					"Connecting Flights", "Check Offer", "PASSED",
-					fmt.Sprintf("Offer ID: %s, Segments: %d", offer.ID, len(offer.Slices[0].Segments)),
+					fmt.Sprintf("Offer ID: %s, Connections: %d", offer.ID, offer.MaxConnections()),
 				}, rowConfigAutoMerge,
 			)
 			break
@@ -182,7 +181,7 @@ func testConnectingFlights(ctx context.Context, client duffel.Duffel, _ duffel.D
 }
 
 func testNoBaggages(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "No Baggages", "BTS", "MRU")
+	_, allOffers := createOfferRequest(ctx, client, t, "No Baggages", duffel.SandboxScenarioNoBaggages)
 	if allOffers == nil {
 		return
 	}
@@ -208,7 +207,7 @@ func testNoBaggages(ctx context.Context, client duffel.Duffel, _ duffel.Duffel,
 }
 
 func testNoServices(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "No Additional Services", "BTS", "ABV")
+	_, allOffers := createOfferRequest(ctx, client, t, "No Additional Services", duffel.SandboxScenarioNoServices)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -243,7 +242,7 @@ func testNoServices(ctx context.Context, client duffel.Duffel, _ duffel.Duffel,
 }
 
 func testOfferUnavailable(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Offer Unavailable", "LGW", "LHR")
+	_, allOffers := createOfferRequest(ctx, client, t, "Offer Unavailable", duffel.SandboxScenarioOfferUnavailable)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -265,7 +264,7 @@ func testOfferUnavailable(ctx context.Context, client duffel.Duffel, _ duffel.Du
 }
 
 func testOfferPriceChange(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Offer Price Change", "LHR", "STN")
+	_, allOffers := createOfferRequest(ctx, client, t, "Offer Price Change", duffel.SandboxScenarioPriceChange)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -302,7 +301,7 @@ func testOfferPriceChange(ctx context.Context, client duffel.Duffel, _ duffel.Du
 }
 
 func testOrderCreationError(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Order Creation Error", "LHR", "LGW")
+	_, allOffers := createOfferRequest(ctx, client, t, "Order Creation Error", duffel.SandboxScenarioOrderCreationError)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -327,7 +326,7 @@ func testOrderCreationError(ctx context.Context, client duffel.Duffel, _ duffel.
 }
 
 func testInsufficientBalance(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Insufficient Balance", "LGW", "STN")
+	_, allOffers := createOfferRequest(ctx, client, t, "Insufficient Balance", duffel.SandboxScenarioInsufficientBalance)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -353,7 +352,7 @@ func testInsufficientBalance(ctx context.Context, client duffel.Duffel, _ duffel
 }
 
 func testCardPaymentSuccess(ctx context.Context, client duffel.Duffel, cardsAPIClient duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Card Payment Success", "LTN", "STN")
+	_, allOffers := createOfferRequest(ctx, client, t, "Card Payment Success", duffel.SandboxScenarioCardPayment)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -386,7 +385,7 @@ func testCardPaymentSuccess(ctx context.Context, client duffel.Duffel, cardsAPIC
 }
 
 func testAirlineInitiatedChange(ctx context.Context, client duffel.Duffel, _ duffel.Duffel, t table.Writer) {
-	_, allOffers := createOfferRequest(ctx, client, t, "Airline-Initiated Change", "LHR", "LTN")
+	_, allOffers := createOfferRequest(ctx, client, t, "Airline-Initiated Change", duffel.SandboxScenarioAirlineInitiatedChange)
 	if len(allOffers) == 0 {
 		return
 	}
@@ -434,34 +433,39 @@ func createOrder(
 	ctx context.Context, client duffel.Duffel, offer *duffel.Offer, paymentMethod duffel.PaymentMethod,
 	cardID ...string,
 ) (*duffel.Order, error) {
+	input := duffel.CreateOrderInput{
+		Type:           duffel.OrderTypeInstant,
+		SelectedOffers: []string{offer.ID},
+		Passengers: []duffel.OrderPassenger{
+			{
+				ID:          offer.Passengers[0].ID,
+				Title:       duffel.PassengerTitleMrs,
+				GivenName:   "Amelia",
+				FamilyName:  "Earhart",
+				Gender:      duffel.GenderFemale,
+				BornOn:      duffel.Date(time.Now().AddDate(-30, 0, 0)),
+				Email:       "amelia@duffel.com",
+				PhoneNumber: "+442080160509",
+			},
+		},
+	}
+
+	amount, err := input.ComputeRequiredPayment(offer)
+	if err != nil {
+		return nil, err
+	}
+
 	payment := duffel.PaymentCreateInput{
 		Type:     paymentMethod,
-		Amount:   offer.RawTotalAmount,
-		Currency: offer.RawTotalCurrency,
+		Amount:   amount.Number(),
+		Currency: amount.CurrencyCode(),
 	}
 	if paymentMethod == duffel.PaymentMethodCard && len(cardID) > 0 {
 		payment.CardID = cardID[0]
 	}
+	input.Payments = []duffel.PaymentCreateInput{payment}
 
-	return client.CreateOrder(
-		ctx, duffel.CreateOrderInput{
-			Type:           duffel.OrderTypeInstant,
-			SelectedOffers: []string{offer.ID},
-			Passengers: []duffel.OrderPassenger{
-				{
-					ID:          offer.Passengers[0].ID,
-					Title:       duffel.PassengerTitleMrs,
-					GivenName:   "Amelia",
-					FamilyName:  "Earhart",
-					Gender:      duffel.GenderFemale,
-					BornOn:      duffel.Date(time.Now().AddDate(-30, 0, 0)),
-					Email:       "amelia@duffel.com",
-					PhoneNumber: "+442080160509",
-				},
-			},
-			Payments: []duffel.PaymentCreateInput{payment},
-		},
-	)
+	return client.CreateOrder(ctx, input)
 }
 
 func createTemporaryPaymentCard(ctx context.Context, cardsAPIClient duffel.Duffel) (*duffel.PaymentCard, error) {
@@ -2,6 +2,11 @@ package duffel
 
 import "time"
 
+// ConnectionCount returns the number of connections of the slice, zero for a direct flight.
+func (sl *Slice) ConnectionCount() int {
+	return max(len(sl.Segments)-1, 0)
+}
+
 func (f *Flight) DepartingAt() (time.Time, error) {
 	loc, err := time.LoadLocation(f.Origin.TimeZone)
 	if err != nil {
@@ -29,3 +34,28 @@ func (f *Flight) ArrivingAt() (time.Time, error) {
 
 	return t, err
 }
+
+// HasWiFi returns true if WiFi is available in the cabin of any passenger on the segment.
+func (f *Flight) HasWiFi() bool {
+	for _, passenger := range f.Passengers {
+		if passenger.Cabin != nil && passenger.Cabin.Amenities != nil &&
+			passenger.Cabin.Amenities.WiFi != nil && passenger.Cabin.Amenities.WiFi.Available {
+			return true
+		}
+	}
+	return false
+}
+
+// SeatPitch returns the seat pitch of the amenities, or an empty string if unknown.
+func (a *Amenities) SeatPitch() string {
+	if a == nil || a.Seat == nil || a.Seat.Pitch == "n/a" {
+		return ""
+	}
+	return a.Seat.Pitch
+}
+
+// IsCodeshare returns true if the segment is operated by another airline than the one marketing it.
+func (f *Flight) IsCodeshare() bool {
+	return f.OperatingCarrier.IATACode != "" && f.MarketingCarrier.IATACode != "" &&
+		f.OperatingCarrier.IATACode != f.MarketingCarrier.IATACode
+}
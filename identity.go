@@ -0,0 +1,75 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const liveTokenPrefix = "duffel_live_"
+
+// ErrInvalidToken is returned by Ping when Duffel rejects the API token.
+var ErrInvalidToken = fmt.Errorf("duffel: invalid API token")
+
+type (
+	// IdentityClient is implemented by the client returned by New. It is kept out of Duffel so that
+	// other implementations, such as mocks, need not provide it: use client.(duffel.IdentityClient).
+	IdentityClient interface {
+		// GetMe verifies the API token and reports the mode it operates in.
+		GetMe(ctx context.Context) (*Me, error)
+
+		// Ping checks that Duffel is reachable and accepts the API token.
+		Ping(ctx context.Context) error
+	}
+
+	// Me describes the account behind the API token.
+	// Duffel does not expose an identity endpoint returning the organisation, so only
+	// the mode of the token, derived from its prefix, is reported.
+	Me struct {
+		LiveMode bool `json:"live_mode"`
+	}
+)
+
+// GetMe verifies the API token and returns the mode it operates in. Duffel has no identity endpoint,
+// so the token is verified with a throwaway authenticated request, GET /air/airlines?limit=1, whose
+// result is discarded. LiveMode is not reported by the API: it is derived from the token prefix,
+// "duffel_live_" for live tokens.
+func (a *API) GetMe(ctx context.Context) (*Me, error) {
+	// Resolve the token once and send it, as a provider may not return the same token twice.
+	token, err := resolveToken(ctx, a.APIToken, a.options)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = newRequestWithAPI[EmptyPayload, Airline](a).
+		Get("/air/airlines", WithURLParam("limit", "1")).
+		Slice(WithToken(ctx, token))
+	if err != nil {
+		return nil, err
+	}
+	return &Me{LiveMode: strings.HasPrefix(token, liveTokenPrefix)}, nil
+}
+
+// Ping makes a minimal read-only authenticated request, suitable for readiness probes.
+// It returns nil on success, an error wrapping ErrInvalidToken when Duffel answers 401,
+// and the underlying error otherwise (e.g. a network failure).
+func (a *API) Ping(ctx context.Context) error {
+	_, err := a.GetMe(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var derr *DuffelError
+	if errors.As(err, &derr) && derr.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, derr)
+	}
+	return err
+}
+
+var _ IdentityClient = (*API)(nil)
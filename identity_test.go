@@ -0,0 +1,86 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetMe(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		MatchParam("limit", "1").
+		MatchHeader("Authorization", "Bearer duffel_live_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-list-airlines.json")
+
+	client := New("duffel_live_123").(IdentityClient)
+	me, err := client.GetMe(context.TODO())
+	a.NoError(err)
+	a.True(me.LiveMode)
+	a.True(gock.IsDone())
+}
+
+func TestPing(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-list-airlines.json")
+
+	client := New("duffel_test_123").(IdentityClient)
+	a.NoError(client.Ping(context.TODO()))
+}
+
+func TestPingInvalidToken(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		Reply(401).
+		JSON(`{"errors": [{"type": "authentication_error", "code": "access_token_not_found", "message": "The access token used is not recognized by our system"}], "meta": {"status": 401, "request_id": "FZW0H3HdJwKk5HMAAKxB"}}`)
+
+	client := New("duffel_test_123").(IdentityClient)
+	err := client.Ping(context.TODO())
+	a.ErrorIs(err, ErrInvalidToken)
+
+	var derr *DuffelError
+	a.ErrorAs(err, &derr)
+	a.True(derr.IsCode(AccessTokenNotFound))
+}
+
+func TestPingNetworkFailure(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		ReplyError(fmt.Errorf("connection refused"))
+
+	client := New("duffel_test_123").(IdentityClient)
+	err := client.Ping(context.TODO())
+	a.Error(err)
+	a.NotErrorIs(err, ErrInvalidToken)
+}
@@ -13,6 +13,9 @@ type Iter[T any] struct {
 	meta     *ListMeta
 	nextPage PageFn[T]
 	values   []*T
+
+	// pageCursor is the cursor the current page was fetched with.
+	pageCursor string
 }
 
 func Collect[T any](it *Iter[T]) ([]*T, error) {
@@ -68,6 +71,20 @@ func (it *Iter[T]) Meta() *ListMeta {
 	return it.meta
 }
 
+// After returns the cursor to resume the iteration from, e.g. after a crash, using a *After
+// method such as ListOrdersAfter. Call it once the current item has been processed: items of
+// the current page not yet visited are not skipped, so at most one page is visited twice.
+// It returns an empty string once the list is exhausted.
+func (it *Iter[T]) After() string {
+	if it == nil {
+		return ""
+	}
+	if len(it.values) > 0 {
+		return it.pageCursor
+	}
+	return it.meta.After
+}
+
 // Next advances the Iter to the next item in the list,
 // which will then be available
 // through the Current method.
@@ -91,8 +108,10 @@ func (it *Iter[T]) Next() bool {
 }
 
 func (it *Iter[T]) getPage() {
+	cursor := it.meta.After
 	it.list, it.err = it.nextPage(it.meta)
 	if it.err == nil {
+		it.pageCursor = cursor
 		it.values = it.list.GetItems()
 		it.meta = it.list.GetListMeta()
 	}
@@ -100,9 +119,14 @@ func (it *Iter[T]) getPage() {
 
 // GetIter returns a new Iter for a given query and type.
 func GetIter[T any](pager PageFn[T]) *Iter[T] {
+	return GetIterAfter(pager, "")
+}
+
+// GetIterAfter returns a new Iter for a given query and type, starting from the page at the given cursor.
+func GetIterAfter[T any](pager PageFn[T], cursor string) *Iter[T] {
 	iter := &Iter[T]{
 		nextPage: pager,
-		meta:     &ListMeta{},
+		meta:     &ListMeta{After: cursor},
 	}
 
 	iter.getPage()
@@ -0,0 +1,135 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// fakeClock is a Clock that only advances when Sleep is called, recording each sleep.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+func TestIterPausesForRateLimit(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	// Every page reports a limit of 1 request per 30 seconds.
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	pages := []string{
+		`{"meta": {"limit": 1, "after": "cursor_1"}, "data": [{"id": "arl_1"}]}`,
+		`{"meta": {"limit": 1, "after": "cursor_2"}, "data": [{"id": "arl_2"}]}`,
+		`{"meta": {"limit": 1, "after": null}, "data": [{"id": "arl_3"}]}`,
+	}
+	for _, page := range pages {
+		gock.New("https://api.duffel.com").
+			Get("/air/airlines").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "1").
+			SetHeader("Ratelimit-Remaining", "1").
+			SetHeader("Ratelimit-Reset", date.Add(30*time.Second).Format(time.RFC1123)).
+			SetHeader("Date", date.Format(time.RFC1123)).
+			JSON(page)
+	}
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithClock(clock))
+	airlines, err := Collect(client.ListAirlines(context.TODO()))
+	a.NoError(err)
+	a.Len(airlines, 3)
+
+	// The first page leaves a token from the initial burst, so only the third page has to wait.
+	a.Equal([]time.Duration{30 * time.Second}, clock.Sleeps())
+}
+
+func TestIterWaitsForExhaustedRateLimit(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	// Every page exhausts the quota of a 30 second window.
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	pages := []string{
+		`{"meta": {"limit": 1, "after": "cursor_1"}, "data": [{"id": "arl_1"}]}`,
+		`{"meta": {"limit": 1, "after": "cursor_2"}, "data": [{"id": "arl_2"}]}`,
+		`{"meta": {"limit": 1, "after": null}, "data": [{"id": "arl_3"}]}`,
+	}
+	for i, page := range pages {
+		window := date.Add(time.Duration(i) * 30 * time.Second)
+		gock.New("https://api.duffel.com").
+			Get("/air/airlines").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", "0").
+			SetHeader("Ratelimit-Reset", window.Add(30*time.Second).Format(time.RFC1123)).
+			SetHeader("Date", window.Format(time.RFC1123)).
+			JSON(page)
+	}
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRateLimitThrottling(), WithClock(clock))
+	airlines, err := Collect(client.ListAirlines(context.TODO()))
+	a.NoError(err)
+	a.Len(airlines, 3)
+
+	// Each page after the first waits for the window exhausted by the previous one to reset.
+	a.Equal([]time.Duration{30 * time.Second, 30 * time.Second}, clock.Sleeps())
+}
+
+func TestIterFailsOnExhaustedRateLimitWithoutThrottling(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", date.Add(30*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		JSON(`{"meta": {"limit": 1, "after": "cursor_1"}, "data": [{"id": "arl_1"}]}`)
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithClock(clock))
+	_, err := Collect(client.ListAirlines(context.TODO()))
+	rateLimit, ok := RateLimitFromError(err)
+	if a.True(ok) {
+		a.Equal(0, rateLimit.Remaining)
+	}
+	a.Empty(clock.Sleeps())
+}
@@ -0,0 +1,65 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// log writes a record to the logger set with WithLogger, if any.
+func (c *client[R, T]) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.options.Logger == nil {
+		return
+	}
+	c.options.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logAttempt logs the outcome of one attempt of a request: responses at debug level,
+// and errors at warning level.
+func (c *client[R, T]) logAttempt(req *http.Request, attempt int, took time.Duration, resp *http.Response, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", took),
+	}
+
+	var derr *DuffelError
+	switch {
+	case err == nil:
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("request_id", resp.Header.Get(RequestIDHeader)),
+		)
+		c.log(req.Context(), slog.LevelDebug, "duffel: response", attrs...)
+	case errors.As(err, &derr):
+		attrs = append(attrs,
+			slog.Int("status", derr.StatusCode),
+			slog.String("request_id", derr.Meta.RequestID),
+			slog.String("error", err.Error()),
+		)
+		c.log(req.Context(), slog.LevelWarn, "duffel: error response", attrs...)
+	default:
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.log(req.Context(), slog.LevelWarn, "duffel: request failed", attrs...)
+	}
+}
+
+// logRateLimit logs the rate limit reported by a response, at warning level once it is exhausted.
+func (c *client[R, T]) logRateLimit(ctx context.Context, rateLimit *RateLimit) {
+	level := slog.LevelDebug
+	if rateLimit.Remaining == 0 {
+		level = slog.LevelWarn
+	}
+	c.log(ctx, level, "duffel: rate limit",
+		slog.Int("limit", rateLimit.Limit),
+		slog.Int("remaining", rateLimit.Remaining),
+		slog.Time("reset_at", rateLimit.ResetAt),
+	)
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWithLogger(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("x-request-id", "FZW0H3HdJwKk5HMAAKxB").
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "59").
+		SetHeader("Ratelimit-Reset", time.Now().Add(time.Minute).Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := New("duffel_test_123", WithLogger(logger), WithRetries(2, time.Second), WithClock(newFakeClock()))
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		a.NoError(json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	if !a.Len(records, 4) {
+		return
+	}
+
+	a.Equal("duffel: error response", records[0]["msg"])
+	a.Equal("WARN", records[0]["level"])
+	a.Equal(float64(503), records[0]["status"])
+
+	a.Equal("duffel: retrying request", records[1]["msg"])
+	a.Equal("INFO", records[1]["level"])
+	a.Equal(float64(2), records[1]["attempt"])
+
+	a.Equal("duffel: response", records[2]["msg"])
+	a.Equal("DEBUG", records[2]["level"])
+	a.Equal("/air/orders/ord_123", records[2]["path"])
+	a.Equal("FZW0H3HdJwKk5HMAAKxB", records[2]["request_id"])
+
+	a.Equal("duffel: rate limit", records[3]["msg"])
+	a.Equal(float64(59), records[3]["remaining"])
+}
@@ -17,6 +17,12 @@ type (
 		ListLoyaltyProgramme(ctx context.Context) *Iter[LoyaltyProgramme]
 		GetLoyaltyProgramme(ctx context.Context, id string) (*LoyaltyProgramme, error)
 	}
+
+	// LoyaltyProgrammeResolver is implemented by the client returned by New. It is kept out of Duffel so that
+	// other implementations, such as mocks, need not provide it: use client.(duffel.LoyaltyProgrammeResolver).
+	LoyaltyProgrammeResolver interface {
+		ResolveLoyaltyProgrammes(ctx context.Context, codes []string) ([]*LoyaltyProgramme, error)
+	}
 )
 
 // ListLoyaltyProgramme retrieves a paginated list of loyalty programmes.
@@ -33,4 +39,83 @@ func (a *API) GetLoyaltyProgramme(ctx context.Context, id string) (*LoyaltyProgr
 		Single(ctx)
 }
 
+// ResolveLoyaltyProgrammes maps airline IATA codes, as found in Offer.SupportedLoyaltyProgrammes,
+// to the loyalty programmes owned by those airlines, in the order of the codes.
+// Codes without a known programme, and repeated codes, are skipped.
+//
+// Loyalty programmes only reference their owner airline by ID, so the first call lists every programme
+// and matches their owners against the list of airlines; the result is cached for the lifetime of the client.
+func (a *API) ResolveLoyaltyProgrammes(ctx context.Context, codes []string) ([]*LoyaltyProgramme, error) {
+	programmes, err := a.loyaltyProgrammesByCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]*LoyaltyProgramme, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		if programme, ok := programmes[code]; ok {
+			resolved = append(resolved, programme)
+		}
+	}
+	return resolved, nil
+}
+
+// loyaltyProgrammesByCode returns the cached loyalty programmes, loading them on first use. The lock is
+// not held while loading, so concurrent first calls may each load them, the first load to finish being kept.
+func (a *API) loyaltyProgrammesByCode(ctx context.Context) (map[string]*LoyaltyProgramme, error) {
+	a.loyaltyMu.Lock()
+	programmes := a.loyaltyProgrammes
+	a.loyaltyMu.Unlock()
+	if programmes != nil {
+		return programmes, nil
+	}
+
+	programmes, err := a.loadLoyaltyProgrammes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.loyaltyMu.Lock()
+	defer a.loyaltyMu.Unlock()
+	if a.loyaltyProgrammes == nil {
+		a.loyaltyProgrammes = programmes
+	}
+	return a.loyaltyProgrammes, nil
+}
+
+func (a *API) loadLoyaltyProgrammes(ctx context.Context) (map[string]*LoyaltyProgramme, error) {
+	programmes, err := Collect(a.ListLoyaltyProgramme(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	byOwner := make(map[string][]*LoyaltyProgramme, len(programmes))
+	for _, programme := range programmes {
+		byOwner[programme.OwnerAirlineID] = append(byOwner[programme.OwnerAirlineID], programme)
+	}
+
+	// Listing airlines takes a few pages where retrieving each owner would take a request per programme.
+	// The listing stops once every owner is found.
+	byCode := make(map[string]*LoyaltyProgramme, len(programmes))
+	iter := a.ListAirlines(ctx)
+	for len(byOwner) > 0 && iter.Next() {
+		airline := iter.Current()
+		for _, programme := range byOwner[airline.ID] {
+			byCode[airline.IATACode] = programme
+		}
+		delete(byOwner, airline.ID)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return byCode, nil
+}
+
 var _ LoyaltyProgrammeClient = (*API)(nil)
+
+var _ LoyaltyProgrammeResolver = (*API)(nil)
@@ -0,0 +1,54 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestResolveLoyaltyProgrammes(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/loyalty_programmes").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"meta":{"limit":50,"after":null},"data":[` +
+			`{"id":"lyp_ba","name":"Executive Club","owner_airline_id":"arl_ba"},` +
+			`{"id":"lyp_ib","name":"Iberia Plus","owner_airline_id":"arl_ib"}]}`)
+	// Every owner is on the first page of airlines, so the second one is not requested.
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"meta":{"limit":50,"after":"g2wAAAACZAAEbmFtZW0AAAAK"},"data":[` +
+			`{"id":"arl_ba","iata_code":"BA"},{"id":"arl_u2","iata_code":"U2"},{"id":"arl_ib","iata_code":"IB"}]}`)
+
+	client := New("duffel_test_123").(LoyaltyProgrammeResolver)
+	programmes, err := client.ResolveLoyaltyProgrammes(context.TODO(), []string{"IB", "U2", "BA", "IB"})
+	a.NoError(err)
+	if a.Len(programmes, 2) {
+		a.Equal("Iberia Plus", programmes[0].Name)
+		a.Equal("Executive Club", programmes[1].Name)
+	}
+	a.True(gock.IsDone())
+
+	// Served from the cache, no request is made.
+	programmes, err = client.ResolveLoyaltyProgrammes(context.TODO(), []string{"BA"})
+	a.NoError(err)
+	a.Len(programmes, 1)
+}
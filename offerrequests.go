@@ -6,11 +6,21 @@ package duffel
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+// ErrNoOffers is returned by CreateOfferRequestWithOffers when no airline returned an offer,
+// if enabled with WithNoOffersError.
+var ErrNoOffers = fmt.Errorf("duffel: no offers returned")
+
+// maxConcurrentOfferRequests bounds the number of requests CreateOfferRequests has in flight.
+const maxConcurrentOfferRequests = 5
+
 type (
 	OfferRequestClient interface {
 		CreateOfferRequest(ctx context.Context, requestInput OfferRequestInput) (*OfferRequest, error)
@@ -21,6 +31,18 @@ type (
 		ListOfferRequests(ctx context.Context) *Iter[OfferRequest]
 	}
 
+	// OfferRequestBatcher is implemented by the client returned by New. It is kept out of Duffel so that
+	// other implementations, such as mocks, need not provide it: use client.(duffel.OfferRequestBatcher).
+	OfferRequestBatcher interface {
+		CreateOfferRequests(ctx context.Context, inputs []OfferRequestInput) ([]*OfferRequest, []error)
+	}
+
+	// OfferRequestWithOffersCreator is implemented by the client returned by New. It is kept out of Duffel so
+	// that other implementations, such as mocks, need not provide it: use client.(duffel.OfferRequestWithOffersCreator).
+	OfferRequestWithOffersCreator interface {
+		CreateOfferRequestWithOffers(ctx context.Context, requestInput OfferRequestInput) (*OfferRequest, error)
+	}
+
 	OfferRequestInput struct {
 		// The passengers who want to travel. If you specify an age for a passenger, the type may differ for the same passenger in different offers due to airline's different rules. e.g. one airline may treat a 14 year old as an adult, and another as a young adult. You may only specify an age or a type – not both.
 		Passengers []OfferRequestPassenger `json:"passengers" url:"-"`
@@ -88,6 +110,59 @@ func (a *API) CreateOfferRequest(ctx context.Context, requestInput OfferRequestI
 		Single(ctx)
 }
 
+// CreateOfferRequestWithOffers creates an offer request returning its offers, regardless of ReturnOffers.
+// With WithNoOffersError, an offer request without offers is returned along with ErrNoOffers.
+func (a *API) CreateOfferRequestWithOffers(ctx context.Context, requestInput OfferRequestInput) (
+	*OfferRequest, error,
+) {
+	requestInput.ReturnOffers = true
+	offerRequest, err := a.CreateOfferRequest(ctx, requestInput)
+	if err != nil {
+		return nil, err
+	}
+	if len(offerRequest.Offers) == 0 && a.options.NoOffersError {
+		return offerRequest, ErrNoOffers
+	}
+	return offerRequest, nil
+}
+
+// CreateOfferRequests creates one offer request per input, at most maxConcurrentOfferRequests at a time.
+// Results and errors are index-aligned with inputs: for each index, exactly one of them is set.
+// Inputs not started before ctx is done fail with the context error.
+//
+// The requests are paced against Duffel's rate limit: by the throttle of the API token when the client
+// was created with WithRateLimitThrottling, and otherwise by a throttle shared by the batch, starting
+// from the rate limit reported by the client's latest response.
+func (a *API) CreateOfferRequests(ctx context.Context, inputs []OfferRequestInput) ([]*OfferRequest, []error) {
+	if t := a.batchThrottle(); t != nil {
+		ctx = withThrottle(ctx, t)
+	}
+
+	results := make([]*OfferRequest, len(inputs))
+	errs := make([]error, len(inputs))
+
+	sem := make(chan struct{}, maxConcurrentOfferRequests)
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = a.CreateOfferRequest(ctx, input)
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
 func (a *API) CreatePartialOfferRequest(ctx context.Context, requestInput OfferRequestInput) (*OfferRequest, error) {
 	return newRequestWithAPI[OfferRequestInput, OfferRequest](a).
 		Post("/air/partial_offer_requests", &requestInput).
@@ -123,10 +198,80 @@ func (a *API) ListOfferRequests(ctx context.Context) *Iter[OfferRequest] {
 // Encode implements the ParamEncoder interface.
 func (o OfferRequestInput) Encode(q url.Values) error {
 	q.Set("return_offers", strconv.FormatBool(o.ReturnOffers))
+	if o.SupplierTimeout > 0 {
+		q.Set("supplier_timeout", strconv.Itoa(o.SupplierTimeout))
+	}
 	return nil
 }
 
+// RespondingAirlines returns the distinct IATA codes of the airlines that own at least one
+// of the returned offers, in the order they first appear.
+func (r *OfferRequest) RespondingAirlines() []string {
+	seen := make(map[string]bool)
+	codes := make([]string, 0)
+	for _, offer := range r.Offers {
+		if offer.Owner.IATACode == "" || seen[offer.Owner.IATACode] {
+			continue
+		}
+		seen[offer.Owner.IATACode] = true
+		codes = append(codes, offer.Owner.IATACode)
+	}
+	return codes
+}
+
+// MissingAirlines returns the IATA codes from expected that did not return any offer.
+//
+// Duffel does not report which suppliers timed out when OfferRequestInput.SupplierTimeout elapses; the request
+// succeeds with the offers that arrived in time. Comparing the airlines you expected to search
+// against the ones that responded is the only way to tell that results may be incomplete.
+func (r *OfferRequest) MissingAirlines(expected ...string) []string {
+	responded := make(map[string]bool)
+	for _, code := range r.RespondingAirlines() {
+		responded[code] = true
+	}
+
+	missing := make([]string, 0)
+	for _, code := range expected {
+		if !responded[code] {
+			missing = append(missing, code)
+		}
+	}
+	return missing
+}
+
+// IncompleteResultsWarning returns a warning to show users when airlines from expected did not return
+// any offer, e.g. because they did not respond within OfferRequestInput.SupplierTimeout, or an empty
+// string when every expected airline responded. See MissingAirlines.
+func (r *OfferRequest) IncompleteResultsWarning(expected ...string) string {
+	missing := r.MissingAirlines(expected...)
+	switch len(missing) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("results may be incomplete; 1 airline didn't respond in time (%s)", missing[0])
+	default:
+		return fmt.Sprintf(
+			"results may be incomplete; %d airlines didn't respond in time (%s)",
+			len(missing), strings.Join(missing, ", "),
+		)
+	}
+}
+
+// OfferCount returns the number of offers returned with the offer request.
+//
+// Duffel does not report how many offers were found in total: when ReturnOffers is set every offer is
+// embedded in the response, so this is the full count. Without ReturnOffers it is zero, and the offers
+// have to be counted by iterating ListOffers.
+func (r *OfferRequest) OfferCount() int {
+	return len(r.Offers)
+}
+
 func (o PartialOfferRequestInput) Encode(q url.Values) error {
 	q["selected_partial_offer[]"] = o.SelectedPartialOffers
 	return nil
 }
+
+var (
+	_ OfferRequestBatcher           = (*API)(nil)
+	_ OfferRequestWithOffersCreator = (*API)(nil)
+)
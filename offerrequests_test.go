@@ -6,6 +6,7 @@ package duffel
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -129,3 +130,220 @@ func TestListOfferRequests(t *testing.T) {
 	a.Equal("arp_jfk_us", data.Slices[0].Origin.ID)
 	a.Equal("cit_aus_us", data.Slices[0].Destination.ID)
 }
+
+func TestCreateOfferRequestSupplierTimeout(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		MatchParam("return_offers", "true").
+		MatchParam("supplier_timeout", "10000").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	client := New("duffel_test_123")
+	data, err := client.CreateOfferRequest(
+		context.TODO(), OfferRequestInput{
+			ReturnOffers:    true,
+			SupplierTimeout: 10000,
+		},
+	)
+	a.NoError(err)
+	a.ElementsMatch([]string{"AA", "DL", "B6", "ZZ"}, data.RespondingAirlines())
+	a.Equal([]string{"BA", "UA"}, data.MissingAirlines("AA", "BA", "DL", "UA"))
+	a.Empty(data.MissingAirlines("AA", "DL"))
+	a.Equal(
+		"results may be incomplete; 2 airlines didn't respond in time (BA, UA)",
+		data.IncompleteResultsWarning("AA", "BA", "DL", "UA"),
+	)
+	a.Equal("results may be incomplete; 1 airline didn't respond in time (BA)", data.IncompleteResultsWarning("AA", "BA"))
+	a.Empty(data.IncompleteResultsWarning("AA", "DL"))
+	a.Equal(len(data.Offers), data.OfferCount())
+}
+
+func TestCreateOfferRequests(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		BodyString(`"origin":"JFK"`).
+		Times(2).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		BodyString(`"origin":"XXX"`).
+		Reply(422).
+		File("fixtures/422-validation-error.json")
+
+	input := func(origin string) OfferRequestInput {
+		return OfferRequestInput{
+			Passengers: []OfferRequestPassenger{{Type: PassengerTypeAdult}},
+			CabinClass: CabinClassEconomy,
+			Slices: []OfferRequestSlice{
+				{DepartureDate: Date(time.Now().AddDate(0, 0, 7)), Origin: origin, Destination: "AUS"},
+			},
+		}
+	}
+
+	client := New("duffel_test_123").(OfferRequestBatcher)
+	results, errs := client.CreateOfferRequests(
+		context.TODO(), []OfferRequestInput{input("JFK"), input("XXX"), input("JFK")},
+	)
+	a.Len(results, 3)
+	a.Len(errs, 3)
+
+	a.NoError(errs[0])
+	a.NotNil(results[0])
+	a.Error(errs[1])
+	a.Nil(results[1])
+	a.NoError(errs[2])
+	a.NotNil(results[2])
+	a.True(gock.IsDone())
+}
+
+func TestCreateOfferRequestsPacedByRateLimit(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	// A single request is left in the window, which resets in 10 seconds.
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/offer_requests/orq_00009htyDGjIfajdNBZRlw").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "1").
+		SetHeader("Ratelimit-Reset", date.Add(10*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		Times(2).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", date.Add(10*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithClock(clock))
+	_, err := client.GetOfferRequest(context.TODO(), "orq_00009htyDGjIfajdNBZRlw")
+	a.NoError(err)
+
+	_, errs := client.(OfferRequestBatcher).CreateOfferRequests(context.TODO(), make([]OfferRequestInput, 2))
+	for _, err := range errs {
+		a.NoError(err)
+	}
+	a.True(gock.IsDone())
+
+	// The first request uses the last of the quota, the second waits for the window to reset
+	// instead of being rejected.
+	a.Equal([]time.Duration{10 * time.Second}, clock.Sleeps())
+}
+
+func TestCreateOfferRequestsResponseMeta(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		Times(maxConcurrentOfferRequests).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	ctx, meta := WithResponseMeta(context.TODO())
+	client := New("duffel_test_123").(OfferRequestBatcher)
+	_, errs := client.CreateOfferRequests(ctx, make([]OfferRequestInput, maxConcurrentOfferRequests))
+	for _, err := range errs {
+		a.NoError(err)
+	}
+	a.Equal(http.StatusOK, meta.StatusCode)
+}
+
+func TestCreateOfferRequestsCancelledContext(t *testing.T) {
+	a := assert.New(t)
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	client := New("duffel_test_123").(OfferRequestBatcher)
+	inputs := make([]OfferRequestInput, maxConcurrentOfferRequests+2)
+	_, errs := client.CreateOfferRequests(ctx, inputs)
+	for _, err := range errs {
+		a.Error(err)
+	}
+}
+
+func TestCreateOfferRequestWithOffers(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		MatchParam("return_offers", "true").
+		BodyString(`"origin":"JFK"`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		MatchParam("return_offers", "true").
+		BodyString(`"origin":"PVD"`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"data": {"id": "orq_123", "offers": []}}`)
+
+	ctx := context.TODO()
+	client := New("duffel_test_123", WithNoOffersError()).(OfferRequestWithOffersCreator)
+
+	offerRequest, err := client.CreateOfferRequestWithOffers(ctx, SandboxOfferRequestInput(SandboxScenarioHoldOrder))
+	a.NoError(err)
+	a.NotEmpty(offerRequest.Offers)
+
+	offerRequest, err = client.CreateOfferRequestWithOffers(ctx, SandboxOfferRequestInput(SandboxScenarioNoFlights))
+	a.ErrorIs(err, ErrNoOffers)
+	a.Equal("orq_123", offerRequest.ID)
+	a.True(gock.IsDone())
+}
+
+func TestCreateOfferRequestWithOffersEmpty(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/offer_requests").
+		MatchParam("return_offers", "true").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"data": {"id": "orq_123", "offers": []}}`)
+
+	client := New("duffel_test_123").(OfferRequestWithOffersCreator)
+	offerRequest, err := client.CreateOfferRequestWithOffers(
+		context.TODO(), SandboxOfferRequestInput(SandboxScenarioNoFlights),
+	)
+	a.NoError(err)
+	a.Empty(offerRequest.Offers)
+	a.True(gock.IsDone())
+}
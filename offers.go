@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,30 +28,35 @@ type (
 		GetOffer(ctx context.Context, id string, params ...GetOfferParams) (*Offer, error)
 	}
 
+	// Offer is a priced itinerary that can be booked with CreateOrder.
+	//
+	// Duffel does not expose the channel an offer comes from (NDC, GDS or a low-cost carrier API):
+	// servicing goes through Duffel whatever the source, so there is no Source field. Route
+	// post-booking servicing by Owner instead.
 	Offer struct {
-		ID                                      string                  `json:"id"`
-		LiveMode                                bool                    `json:"live_mode"`
-		CreatedAt                               time.Time               `json:"created_at"`
-		UpdatedAt                               time.Time               `json:"updated_at"`
-		ExpiresAt                               time.Time               `json:"expires_at"`
-		TotalEmissionsKg                        interface{}             `json:"total_emissions_kg"`
-		RawTotalCurrency                        string                  `json:"total_currency"`
-		RawTotalAmount                          string                  `json:"total_amount"`
-		RawTaxAmount                            string                  `json:"tax_amount"`
-		RawTaxCurrency                          string                  `json:"tax_currency"`
-		RawBaseAmount                           string                  `json:"base_amount"`
-		RawBaseCurrency                         string                  `json:"base_currency"`
-		Owner                                   Airline                 `json:"owner"`
-		Slices                                  []Slice                 `json:"slices"`
-		Passengers                              []OfferRequestPassenger `json:"passengers"`
-		Partial                                 bool                    `json:"partial"`
-		PassengerIdentityDocumentsRequired      bool                    `json:"passenger_identity_documents_required"`
-		SupportedPassengerIdentityDocumentTypes []string                `json:"supported_passenger_identity_document_types"` // e.g. ["passport"]
-		PaymentRequirements                     OfferPaymentRequirement `json:"payment_requirements"`
-		AvailableServices                       []AvailableService      `json:"available_services"`
-		Conditions                              Conditions              `json:"conditions"`
-		PrivateFares                            []OfferPrivateFare      `json:"private_fares"`
-		SupportedLoyaltyProgrammes              []string                `json:"supported_loyalty_programmes"` // Airline IATA codes (e.g. ["BA", "U2"])
+		ID                                      string                          `json:"id"`
+		LiveMode                                bool                            `json:"live_mode"`
+		CreatedAt                               time.Time                       `json:"created_at"`
+		UpdatedAt                               time.Time                       `json:"updated_at"`
+		ExpiresAt                               time.Time                       `json:"expires_at"`
+		TotalEmissionsKg                        interface{}                     `json:"total_emissions_kg"`
+		RawTotalCurrency                        string                          `json:"total_currency"`
+		RawTotalAmount                          string                          `json:"total_amount"`
+		RawTaxAmount                            string                          `json:"tax_amount"`
+		RawTaxCurrency                          string                          `json:"tax_currency"`
+		RawBaseAmount                           string                          `json:"base_amount"`
+		RawBaseCurrency                         string                          `json:"base_currency"`
+		Owner                                   Airline                         `json:"owner"`
+		Slices                                  []Slice                         `json:"slices"`
+		Passengers                              []OfferRequestPassenger         `json:"passengers"`
+		Partial                                 bool                            `json:"partial"`
+		PassengerIdentityDocumentsRequired      bool                            `json:"passenger_identity_documents_required"`
+		SupportedPassengerIdentityDocumentTypes []PassengerIdentityDocumentType `json:"supported_passenger_identity_document_types"` // e.g. ["passport"]
+		PaymentRequirements                     OfferPaymentRequirement         `json:"payment_requirements"`
+		AvailableServices                       []AvailableService              `json:"available_services"`
+		Conditions                              Conditions                      `json:"conditions"`
+		PrivateFares                            []OfferPrivateFare              `json:"private_fares"`
+		SupportedLoyaltyProgrammes              []string                        `json:"supported_loyalty_programmes"` // Airline IATA codes (e.g. ["BA", "U2"])
 	}
 
 	PrivateFareType string
@@ -103,6 +110,34 @@ type (
 
 	MealType string
 
+	// CancelForAnyReasonTerms are the terms of a cancel for any reason service, to present before purchase.
+	// Claims are made with the provider described in the terms; Duffel has no claim endpoint.
+	CancelForAnyReasonTerms struct {
+		MerchantCopy          string
+		TermsAndConditionsURL string
+		// RefundAmount is the amount refunded on a claim, in the service currency.
+		RefundAmount currency.Amount
+	}
+
+	// FareRules gathers the fare rules of an offer, as far as Duffel exposes them.
+	//
+	// Duffel has no fare rules endpoint and returns no rule text: advance purchase or minimum stay
+	// rules are not available. The structured refund and change conditions are all there is, and the
+	// full rules are found in the owner airline's conditions of carriage.
+	FareRules struct {
+		RefundBeforeDeparture   *ChangeCondition
+		ChangeBeforeDeparture   *ChangeCondition
+		Slices                  []SliceFareRules
+		ConditionsOfCarriageURL string
+	}
+
+	// SliceFareRules are the fare rules that apply to a single slice of an offer.
+	SliceFareRules struct {
+		SliceID               string
+		FareBrandName         string
+		ChangeBeforeDeparture *ChangeCondition
+	}
+
 	OfferPaymentRequirement struct {
 		RequiresInstantPayment  bool      `json:"requires_instant_payment"`
 		PriceGuaranteeExpiresAt *DateTime `json:"price_guarantee_expires_at"`
@@ -118,6 +153,10 @@ type (
 
 	GetOfferParams struct {
 		ReturnAvailableServices bool
+		// ServiceTypes restricts the returned available services to the given types and implies
+		// ReturnAvailableServices. Duffel only supports returning all services, so the filtering
+		// happens client-side: it does not reduce the response payload.
+		ServiceTypes []ServiceType
 	}
 )
 
@@ -186,10 +225,26 @@ func (a *API) GetOffer(ctx context.Context, offerID string, params ...GetOfferPa
 		return nil, fmt.Errorf("offerID should begin with %s", offerIDPrefix)
 	}
 
-	return newRequestWithAPI[GetOfferParams, Offer](a).
+	offer, err := newRequestWithAPI[GetOfferParams, Offer](a).
 		Getf("/air/offers/%s", offerID).
 		WithParams(normalizeParams(params)...).
 		Single(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var serviceTypes []ServiceType
+	for _, p := range params {
+		serviceTypes = append(serviceTypes, p.ServiceTypes...)
+	}
+	if len(serviceTypes) > 0 {
+		offer.AvailableServices = slices.DeleteFunc(
+			offer.AvailableServices, func(s AvailableService) bool {
+				return !slices.Contains(serviceTypes, ServiceType(s.Type))
+			},
+		)
+	}
+	return offer, nil
 }
 
 func (o ListOffersParams) Encode(q url.Values) error {
@@ -205,7 +260,7 @@ func (o ListOffersParams) Encode(q url.Values) error {
 }
 
 func (o GetOfferParams) Encode(q url.Values) error {
-	if o.ReturnAvailableServices {
+	if o.ReturnAvailableServices || len(o.ServiceTypes) > 0 {
 		q.Set("return_available_services", "true")
 	}
 	return nil
@@ -234,6 +289,360 @@ func (o *Offer) TaxAmount() currency.Amount {
 	return amount
 }
 
+// AmountsConsistent returns true if the base and tax amounts add up to the total amount,
+// to the precision of the currency. It fails if any amount is missing or cannot be parsed,
+// or if they are in different currencies, since the breakdown cannot be checked then.
+func (o *Offer) AmountsConsistent() (bool, error) {
+	total, err := currency.NewAmount(o.RawTotalAmount, o.RawTotalCurrency)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse total amount: %w", err)
+	}
+	base, err := currency.NewAmount(o.RawBaseAmount, o.RawBaseCurrency)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse base amount: %w", err)
+	}
+	tax, err := currency.NewAmount(o.RawTaxAmount, o.RawTaxCurrency)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse tax amount: %w", err)
+	}
+
+	sum, err := base.Add(tax)
+	if err != nil {
+		return false, err
+	}
+	return sum.Round().Equal(total.Round()), nil
+}
+
+// PassengerType returns how the offer's airline classified the given passenger, or an empty string
+// if the passenger is not part of the offer. When a passenger is requested by age, this may differ
+// between offers since airlines apply different age rules.
+func (o *Offer) PassengerType(passengerID string) PassengerType {
+	for _, passenger := range o.Passengers {
+		if passenger.ID == passengerID {
+			return passenger.Type
+		}
+	}
+	return ""
+}
+
+// PassengerAgeAtTravel returns the age in years a passenger born on bornOn will have on the first
+// departure date of the offer, or today if the offer has no segments.
+// The passengerID is not needed to compute the age; see ValidatePassengerAge.
+func (o *Offer) PassengerAgeAtTravel(passengerID string, bornOn Date) int {
+	travel := time.Now()
+	if len(o.Slices) > 0 && len(o.Slices[0].Segments) > 0 {
+		raw := o.Slices[0].Segments[0].RawDepartingAt
+		if len(raw) >= len(DateFormat) {
+			if t, err := time.Parse(DateFormat, raw[:len(DateFormat)]); err == nil {
+				travel = t
+			}
+		}
+	}
+
+	born := time.Time(bornOn)
+	age := travel.Year() - born.Year()
+	if travel.Month() < born.Month() || (travel.Month() == born.Month() && travel.Day() < born.Day()) {
+		age--
+	}
+	return age
+}
+
+// ValidatePassengerAge checks that the age of the passenger at travel is consistent with the type the
+// offer classified them as: infants without seat must be under 2 and children under 18. Adults are not
+// checked, as some airlines treat teenagers as adults.
+func (o *Offer) ValidatePassengerAge(passengerID string, bornOn Date) error {
+	age := o.PassengerAgeAtTravel(passengerID, bornOn)
+	switch o.PassengerType(passengerID) {
+	case "":
+		return fmt.Errorf("passenger %s is not on offer %s", passengerID, o.ID)
+	case PassengerTypeInfantWithoutSeat:
+		if age >= 2 {
+			return fmt.Errorf("passenger %s will be %d at travel, too old to be an infant without seat", passengerID, age)
+		}
+	case PassengerTypeChild:
+		if age >= 18 {
+			return fmt.Errorf("passenger %s will be %d at travel, too old to be a child", passengerID, age)
+		}
+	}
+	if age < 0 {
+		return fmt.Errorf("passenger %s is born after the travel date", passengerID)
+	}
+	return nil
+}
+
+// SupportsService returns true if the offer has at least one available service of the given type.
+// Available services are only returned by GetOffer with ReturnAvailableServices set.
+func (o *Offer) SupportsService(t ServiceType) bool {
+	for _, service := range o.AvailableServices {
+		if ServiceType(service.Type) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableServicesForPassenger returns the available services that apply to the given passenger.
+// Available services are only returned by GetOffer with ReturnAvailableServices set.
+func (o *Offer) AvailableServicesForPassenger(passengerID string) []AvailableService {
+	services := make([]AvailableService, 0)
+	for _, service := range o.AvailableServices {
+		if slices.Contains(service.PassengerIDs, passengerID) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// SupportsDocumentType returns true if the offer accepts the given identity document type for its passengers.
+func (o *Offer) SupportsDocumentType(t PassengerIdentityDocumentType) bool {
+	return slices.Contains(o.SupportedPassengerIdentityDocumentTypes, t)
+}
+
+// IsPartial returns true if the offer comes from a partial offer request. A partial offer only prices
+// some slices of the journey: select it with GetPartialOfferRequests, then GetFullPartialOfferRequest,
+// and book one of the full offers returned instead.
+func (o *Offer) IsPartial() bool {
+	return o.Partial
+}
+
+// IsDirect returns true if every slice of the offer is a single segment with no connections.
+func (o *Offer) IsDirect() bool {
+	if len(o.Slices) == 0 {
+		return false
+	}
+	for _, slice := range o.Slices {
+		if len(slice.Segments) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxConnections returns the highest number of connections across the slices of the offer,
+// zero when every slice is direct.
+func (o *Offer) MaxConnections() int {
+	connections := 0
+	for _, slice := range o.Slices {
+		connections = max(connections, slice.ConnectionCount())
+	}
+	return connections
+}
+
+// FareRules returns the fare rules of the offer. See FareRules for what Duffel exposes.
+func (o *Offer) FareRules() *FareRules {
+	rules := &FareRules{
+		RefundBeforeDeparture:   o.Conditions.RefundBeforeDeparture,
+		ChangeBeforeDeparture:   o.Conditions.ChangeBeforeDeparture,
+		Slices:                  make([]SliceFareRules, 0, len(o.Slices)),
+		ConditionsOfCarriageURL: o.Owner.ConditionsOfCarriageURL,
+	}
+	for _, slice := range o.Slices {
+		rules.Slices = append(
+			rules.Slices, SliceFareRules{
+				SliceID:               slice.ID,
+				FareBrandName:         slice.FareBrandName,
+				ChangeBeforeDeparture: slice.Conditions.ChangeBeforeDeparture,
+			},
+		)
+	}
+	return rules
+}
+
+// TrackingReferences returns the distinct tracking references of the offer's private fares,
+// in order of appearance. Use it to check a reference submitted in the offer request was applied.
+func (o *Offer) TrackingReferences() []string {
+	references := make([]string, 0)
+	for _, fare := range o.PrivateFares {
+		if fare.TrackingReference != "" && !slices.Contains(references, fare.TrackingReference) {
+			references = append(references, fare.TrackingReference)
+		}
+	}
+	return references
+}
+
+// CabinMarketingNames returns the distinct airline-branded cabin names of the offer's segments,
+// e.g. "Club World", in order of appearance.
+func (o *Offer) CabinMarketingNames() []string {
+	names := make([]string, 0)
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			for _, passenger := range segment.Passengers {
+				name := passenger.CabinClassMarketingName
+				if name != "" && !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// CancelForAnyReasonTerms returns the terms of the service if it is a cancel for any reason service.
+func (s *AvailableService) CancelForAnyReasonTerms() (*CancelForAnyReasonTerms, bool) {
+	if ServiceType(s.Type) != ServiceTypeCancel {
+		return nil, false
+	}
+
+	terms := &CancelForAnyReasonTerms{
+		MerchantCopy:          s.Metadata.MerchantCopy,
+		TermsAndConditionsURL: s.Metadata.TermsAndConditionsURL,
+	}
+	if amount, err := currency.NewAmount(s.Metadata.RawRefundAmount, s.RawTotalCurrency); err == nil {
+		terms.RefundAmount = amount
+	}
+	return terms, true
+}
+
+// AircraftTypes returns the distinct IATA aircraft type codes flown on the offer's segments, e.g. "380",
+// in order of appearance. Full details are available from GetAircraft with the segment's Aircraft.ID.
+func (o *Offer) AircraftTypes() []string {
+	types := make([]string, 0)
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			code := segment.Aircraft.IATACode
+			if code != "" && !slices.Contains(types, code) {
+				types = append(types, code)
+			}
+		}
+	}
+	return types
+}
+
+// PerPassengerAmount returns the offer total divided evenly between its passengers, rounded to the
+// currency's minor unit. Duffel prices offers as a whole, with no per-slice nor per-passenger
+// breakdown, so this is an approximation: airlines usually charge children and infants less.
+func (o *Offer) PerPassengerAmount() (currency.Amount, error) {
+	if len(o.Passengers) == 0 {
+		return currency.Amount{}, fmt.Errorf("offer %s has no passengers", o.ID)
+	}
+
+	total, err := currency.NewAmount(o.RawTotalAmount, o.RawTotalCurrency)
+	if err != nil {
+		return currency.Amount{}, err
+	}
+
+	amount, err := total.Div(strconv.Itoa(len(o.Passengers)))
+	if err != nil {
+		return currency.Amount{}, err
+	}
+	return amount.Round(), nil
+}
+
+// Disclosures returns the distinct disclosures of the offer's segments, e.g. "Operated by American Airlines".
+// For codeshare segments without disclosures, an "Operated by" notice is built from the operating carrier.
+func (o *Offer) Disclosures() []string {
+	disclosures := make([]string, 0)
+	add := func(disclosure string) {
+		if !slices.Contains(disclosures, disclosure) {
+			disclosures = append(disclosures, disclosure)
+		}
+	}
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			switch {
+			case len(segment.Disclosures) > 0:
+				for _, disclosure := range segment.Disclosures {
+					add(disclosure)
+				}
+			case segment.IsCodeshare():
+				add("Operated by " + segment.OperatingCarrier.Name)
+			}
+		}
+	}
+	return disclosures
+}
+
+// MinimumBookablePrice returns the lowest price at which the offer can be booked.
+// Duffel does not mark any available service as mandatory, so this is the offer total;
+// it fails if the total cannot be parsed rather than understating the price as zero.
+func (o *Offer) MinimumBookablePrice() (currency.Amount, error) {
+	return currency.NewAmount(o.RawTotalAmount, o.RawTotalCurrency)
+}
+
+// TotalDuration returns the sum of the durations of the offer's slices.
+func (o *Offer) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, slice := range o.Slices {
+		total += time.Duration(slice.Duration)
+	}
+	return total
+}
+
+// IsExpired returns true once the offer's ExpiresAt has passed; it can no longer be booked.
+func (o *Offer) IsExpired() bool {
+	return !time.Now().Before(o.ExpiresAt)
+}
+
+// HoldInfo returns whether the offer can be booked as a hold order, paid later, and if so the time
+// by which it must be paid. until is nil when the offer cannot be held or Duffel gave no deadline.
+func (o *Offer) HoldInfo() (canHold bool, until *time.Time) {
+	if o.PaymentRequirements.RequiresInstantPayment {
+		return false, nil
+	}
+	if o.PaymentRequirements.PaymentRequiredBy != nil {
+		deadline := time.Time(*o.PaymentRequirements.PaymentRequiredBy)
+		until = &deadline
+	}
+	return true, until
+}
+
+// PriceGuaranteeValid returns true if the offer price is guaranteed until a time that has not passed.
+// A nil PriceGuaranteeExpiresAt means the airline gives no guarantee, which is reported as invalid.
+func (r *OfferPaymentRequirement) PriceGuaranteeValid() bool {
+	return r.PriceGuaranteeTimeLeft() > 0
+}
+
+// PriceGuaranteeTimeLeft returns the time left before the price guarantee expires.
+// It returns zero when there is no guarantee or it has already expired.
+func (r *OfferPaymentRequirement) PriceGuaranteeTimeLeft() time.Duration {
+	if r == nil || r.PriceGuaranteeExpiresAt == nil {
+		return 0
+	}
+	return max(time.Until(time.Time(*r.PriceGuaranteeExpiresAt)), 0)
+}
+
+// FilterValidOffers returns the offers that have not expired, in their original order.
+func FilterValidOffers(offers []*Offer) []*Offer {
+	valid := make([]*Offer, 0, len(offers))
+	for _, offer := range offers {
+		if offer != nil && !offer.IsExpired() {
+			valid = append(valid, offer)
+		}
+	}
+	return valid
+}
+
+// CheapestDirectOffer returns the direct offer with the lowest total amount, or nil if none of the offers are direct.
+// Offers whose total amount cannot be parsed are skipped, and an error is returned when direct offers are
+// priced in different currencies, as they cannot be compared.
+func CheapestDirectOffer(offers []*Offer) (*Offer, error) {
+	var (
+		cheapest       *Offer
+		cheapestAmount currency.Amount
+	)
+	for _, offer := range offers {
+		if offer == nil || !offer.IsDirect() {
+			continue
+		}
+		amount, err := currency.NewAmount(offer.RawTotalAmount, offer.RawTotalCurrency)
+		if err != nil {
+			continue
+		}
+		if cheapest == nil {
+			cheapest, cheapestAmount = offer, amount
+			continue
+		}
+		cmp, err := amount.Cmp(cheapestAmount)
+		if err != nil {
+			return nil, fmt.Errorf("duffel: cannot compare offers %s and %s: %w", cheapest.ID, offer.ID, err)
+		}
+		if cmp < 0 {
+			cheapest, cheapestAmount = offer, amount
+		}
+	}
+	return cheapest, nil
+}
+
 // Less will sort ascending by total amount
 func (o Offers) Less(i, j int) bool {
 	cmp, err := o[i].TotalAmount().Cmp(o[j].TotalAmount())
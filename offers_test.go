@@ -6,9 +6,11 @@ package duffel
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/segmentio/encoding/json"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -74,6 +76,45 @@ func TestGetOfferByID(t *testing.T) {
 	a.False(data.PaymentRequirements.RequiresInstantPayment)
 	a.Equal(time.Date(2020, 1, 17, 10, 42, 14, 0, time.UTC).Unix(), time.Time(*data.PaymentRequirements.PriceGuaranteeExpiresAt).Unix())
 	a.Equal(time.Date(2020, 1, 17, 10, 42, 14, 0, time.UTC).Unix(), time.Time(*data.PaymentRequirements.PaymentRequiredBy).Unix())
+	canHold, until := data.HoldInfo()
+	a.True(canHold)
+	a.Equal(time.Date(2020, 1, 17, 10, 42, 14, 0, time.UTC).Unix(), until.Unix())
+	a.True(data.SupportsService(ServiceTypeBaggage))
+	a.False(data.SupportsService(ServiceTypeCancel))
+	a.Equal([]string{"380"}, data.AircraftTypes())
+	a.Len(data.AvailableServicesForPassenger("pas_00009hj8USM7Ncg31cBCLL"), 1)
+	a.Empty(data.AvailableServicesForPassenger("pas_unknown"))
+
+	rules := data.FareRules()
+	a.True(rules.RefundBeforeDeparture.Allowed)
+	a.Equal("100.00 GBP", rules.ChangeBeforeDeparture.PenaltyAmount().String())
+	if a.Len(rules.Slices, 1) {
+		a.Equal("Basic", rules.Slices[0].FareBrandName)
+		a.True(rules.Slices[0].ChangeBeforeDeparture.Allowed)
+	}
+}
+
+func TestGetOfferFiltersServiceTypes(t *testing.T) {
+	defer gock.Off()
+
+	a := assert.New(t)
+	gock.New("https://api.duffel.com").
+		Get("/air/offers/off_00009htYpSCXrwaB9DnUm0").
+		MatchParam("return_available_services", "true").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-offers-off_00009htYpSCXrwaB9DnUm0.json")
+
+	client := New("duffel_test_123")
+	data, err := client.GetOffer(context.TODO(), "off_00009htYpSCXrwaB9DnUm0", GetOfferParams{
+		ServiceTypes: []ServiceType{ServiceTypeSeat},
+	})
+	a.NoError(err)
+	a.Empty(data.AvailableServices)
+	a.True(gock.IsDone())
 }
 
 func TestUpdateOffserPassenger(t *testing.T) {
@@ -131,3 +172,417 @@ func TestListOffers_InavlidID(t *testing.T) {
 	a.EqualError(err, "offerRequestId should begin with orq_")
 	a.Nil(data)
 }
+
+func TestCheapestDirectOffer(t *testing.T) {
+	a := assert.New(t)
+
+	direct := func(id, amount, currency string) *Offer {
+		return &Offer{
+			ID:               id,
+			RawTotalAmount:   amount,
+			RawTotalCurrency: currency,
+			Slices:           []Slice{{Segments: []Flight{{}}}},
+		}
+	}
+	connecting := &Offer{
+		ID:               "off_connecting",
+		RawTotalAmount:   "50.00",
+		RawTotalCurrency: "GBP",
+		Slices:           []Slice{{Segments: []Flight{{}, {}}}},
+	}
+
+	a.True(direct("off_1", "1.00", "GBP").IsDirect())
+	a.False(connecting.IsDirect())
+	a.False((&Offer{}).IsDirect())
+
+	offers := []*Offer{
+		connecting,
+		direct("off_malformed", "free", "GBP"),
+		direct("off_expensive", "200.00", "GBP"),
+		direct("off_cheap", "120.00", "GBP"),
+	}
+	cheapest, err := CheapestDirectOffer(offers)
+	if a.NoError(err) {
+		a.Equal("off_cheap", cheapest.ID)
+	}
+
+	_, err = CheapestDirectOffer(append(offers, direct("off_other_currency", "10.00", "USD")))
+	a.ErrorContains(err, "cannot compare offers off_cheap and off_other_currency")
+
+	cheapest, err = CheapestDirectOffer([]*Offer{connecting})
+	a.NoError(err)
+	a.Nil(cheapest)
+	cheapest, err = CheapestDirectOffer(nil)
+	a.NoError(err)
+	a.Nil(cheapest)
+}
+
+func TestOfferPassengerType(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		Passengers: []OfferRequestPassenger{
+			{ID: "pas_adult", Type: PassengerTypeAdult},
+			{ID: "pas_teen", Age: 14, Type: PassengerTypeAdult},
+		},
+	}
+
+	a.Equal(PassengerTypeAdult, offer.PassengerType("pas_teen"))
+	a.Equal(PassengerType(""), offer.PassengerType("pas_unknown"))
+}
+
+func TestListOffersSortParamEncoding(t *testing.T) {
+	for _, sort := range []ListOffersSortParam{ListOffersSortTotalAmount, ListOffersSortTotalDuration} {
+		t.Run(string(sort), func(t *testing.T) {
+			defer gock.Off()
+			a := assert.New(t)
+
+			gock.New("https://api.duffel.com").
+				Get("/air/offers").
+				MatchParam("offer_request_id", "orq_00009htyDGjIfajdNBZRlw").
+				MatchParam("sort", string(sort)).
+				Reply(200).
+				SetHeader("Ratelimit-Limit", "5").
+				SetHeader("Ratelimit-Remaining", "5").
+				SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+				SetHeader("Date", time.Now().Format(time.RFC1123)).
+				JSON(`{"meta": {"limit": 1, "after": "cursor_1"}, "data": [{"id": "off_1"}]}`)
+
+			gock.New("https://api.duffel.com").
+				Get("/air/offers").
+				MatchParam("offer_request_id", "orq_00009htyDGjIfajdNBZRlw").
+				MatchParam("sort", string(sort)).
+				MatchParam("after", "cursor_1").
+				Reply(200).
+				SetHeader("Ratelimit-Limit", "5").
+				SetHeader("Ratelimit-Remaining", "5").
+				SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+				SetHeader("Date", time.Now().Format(time.RFC1123)).
+				JSON(`{"meta": {"limit": 1, "after": null}, "data": [{"id": "off_2"}]}`)
+
+			client := New("duffel_test_123")
+			offers, err := Collect(client.ListOffers(context.TODO(), "orq_00009htyDGjIfajdNBZRlw", ListOffersParams{
+				Sort: sort,
+			}))
+			a.NoError(err)
+			a.Len(offers, 2)
+			a.True(gock.IsDone())
+		})
+	}
+}
+
+func TestListOffersParamsPersistAcrossPages(t *testing.T) {
+	defer gock.Off()
+	defer gock.Observe(nil)
+	a := assert.New(t)
+
+	pages := []struct {
+		after string
+		body  string
+	}{
+		{"", `{"meta": {"limit": 1, "after": "cursor_1"}, "data": [{"id": "off_1"}]}`},
+		{"cursor_1", `{"meta": {"limit": 1, "after": "cursor_2"}, "data": [{"id": "off_2"}]}`},
+		{"cursor_2", `{"meta": {"limit": 1, "after": null}, "data": [{"id": "off_3"}]}`},
+	}
+	for _, page := range pages {
+		mock := gock.New("https://api.duffel.com").Get("/air/offers")
+		if page.after != "" {
+			mock.MatchParam("after", page.after)
+		}
+		mock.Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", "5").
+			SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+			SetHeader("Date", time.Now().Format(time.RFC1123)).
+			JSON(page.body)
+	}
+
+	var requests []*http.Request
+	gock.Observe(func(req *http.Request, _ gock.Mock) {
+		requests = append(requests, req)
+	})
+
+	client := New("duffel_test_123")
+	offers, err := Collect(client.ListOffers(context.TODO(), "orq_00009htyDGjIfajdNBZRlw", ListOffersParams{
+		Sort:           ListOffersSortTotalDuration,
+		MaxConnections: 1,
+	}))
+	a.NoError(err)
+	a.Len(offers, 3)
+	a.True(gock.IsDone())
+
+	a.Len(requests, 3)
+	for i, req := range requests {
+		q := req.URL.Query()
+		a.Equal("orq_00009htyDGjIfajdNBZRlw", q.Get("offer_request_id"), "page %d", i+1)
+		a.Equal("total_duration", q.Get("sort"), "page %d", i+1)
+		a.Equal("1", q.Get("max_connections"), "page %d", i+1)
+		a.Len(q["after"], min(i, 1), "page %d", i+1)
+		a.Equal(pages[i].after, q.Get("after"), "page %d", i+1)
+	}
+}
+
+func TestOfferSupportsDocumentType(t *testing.T) {
+	a := assert.New(t)
+
+	offer := new(Offer)
+	err := json.Unmarshal([]byte(`{"supported_passenger_identity_document_types": ["passport", "known_traveler_number"]}`), offer)
+	a.NoError(err)
+
+	a.True(offer.SupportsDocumentType(PassengerIdentityDocumentTypePassport))
+	a.True(offer.SupportsDocumentType(PassengerIdentityDocumentTypeKnownTravelerNumber))
+	a.False(offer.SupportsDocumentType(PassengerIdentityDocumentTypeTaxID))
+}
+
+func TestOfferMinimumBookablePrice(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{RawTotalAmount: "45.00", RawTotalCurrency: "GBP"}
+	price, err := offer.MinimumBookablePrice()
+	a.NoError(err)
+	a.Equal("45.00 GBP", price.String())
+
+	_, err = (&Offer{RawTotalAmount: "", RawTotalCurrency: "GBP"}).MinimumBookablePrice()
+	a.Error(err)
+}
+
+func TestOfferTrackingReferences(t *testing.T) {
+	a := assert.New(t)
+
+	var offer Offer
+	err := json.Unmarshal([]byte(`{"private_fares": [
+		{"type": "corporate", "corporate_code": "FLX53", "tracking_reference": "ABN:2345678"},
+		{"type": "leisure", "tracking_reference": "ABN:2345678"},
+		{"type": "negotiated", "tracking_reference": "AGY:1"},
+		{"type": "negotiated"}
+	]}`), &offer)
+	a.NoError(err)
+	a.Equal([]string{"ABN:2345678", "AGY:1"}, offer.TrackingReferences())
+	a.Empty((&Offer{}).TrackingReferences())
+}
+
+func TestOfferCabinMarketingNames(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		Slices: []Slice{
+			{
+				Segments: []Flight{
+					{Passengers: []SegmentPassenger{{CabinClassMarketingName: "Club World"}, {CabinClassMarketingName: "Club World"}}},
+					{Passengers: []SegmentPassenger{{CabinClassMarketingName: "Economy Light"}, {}}},
+				},
+			},
+		},
+	}
+	a.Equal([]string{"Club World", "Economy Light"}, offer.CabinMarketingNames())
+	a.Empty((&Offer{}).CabinMarketingNames())
+}
+
+func TestAvailableServiceCancelForAnyReasonTerms(t *testing.T) {
+	a := assert.New(t)
+
+	service := &AvailableService{
+		Type:             string(ServiceTypeCancel),
+		RawTotalCurrency: "GBP",
+		Metadata: AvailableServiceMetadata{
+			MerchantCopy:          "Refund up to 75%",
+			RawRefundAmount:       "100.00",
+			TermsAndConditionsURL: "https://example.com/terms",
+		},
+	}
+	terms, ok := service.CancelForAnyReasonTerms()
+	a.True(ok)
+	a.Equal("Refund up to 75%", terms.MerchantCopy)
+	a.Equal("https://example.com/terms", terms.TermsAndConditionsURL)
+	a.Equal("100.00 GBP", terms.RefundAmount.String())
+
+	_, ok = (&AvailableService{Type: string(ServiceTypeSeat)}).CancelForAnyReasonTerms()
+	a.False(ok)
+}
+
+func TestOfferPassengerAgeAtTravel(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		ID: "off_123",
+		Passengers: []OfferRequestPassenger{
+			{ID: "pas_adult", Type: PassengerTypeAdult},
+			{ID: "pas_child", Type: PassengerTypeChild},
+			{ID: "pas_infant", Type: PassengerTypeInfantWithoutSeat},
+		},
+		Slices: []Slice{{Segments: []Flight{{RawDepartingAt: "2024-06-15T10:00:00"}}}},
+	}
+	born := func(year, month, day int) Date {
+		return Date(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC))
+	}
+
+	a.Equal(17, offer.PassengerAgeAtTravel("pas_child", born(2006, 6, 16)))
+	a.Equal(18, offer.PassengerAgeAtTravel("pas_child", born(2006, 6, 15)))
+
+	a.NoError(offer.ValidatePassengerAge("pas_child", born(2006, 6, 16)))
+	a.Error(offer.ValidatePassengerAge("pas_child", born(2006, 6, 15)))
+	a.NoError(offer.ValidatePassengerAge("pas_infant", born(2022, 6, 16)))
+	a.Error(offer.ValidatePassengerAge("pas_infant", born(2022, 6, 15)))
+	a.NoError(offer.ValidatePassengerAge("pas_adult", born(1990, 1, 1)))
+	a.Error(offer.ValidatePassengerAge("pas_adult", born(2025, 1, 1)))
+	a.Error(offer.ValidatePassengerAge("pas_unknown", born(1990, 1, 1)))
+}
+
+func TestOfferPerPassengerAmount(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		RawTotalAmount:   "100.00",
+		RawTotalCurrency: "GBP",
+		Passengers:       []OfferRequestPassenger{{ID: "pas_1"}, {ID: "pas_2"}, {ID: "pas_3"}},
+	}
+	amount, err := offer.PerPassengerAmount()
+	a.NoError(err)
+	a.Equal("33.33 GBP", amount.String())
+
+	_, err = (&Offer{RawTotalAmount: "100.00", RawTotalCurrency: "GBP"}).PerPassengerAmount()
+	a.Error(err)
+}
+
+func TestSegmentAmenities(t *testing.T) {
+	a := assert.New(t)
+
+	var segment Flight
+	err := json.Unmarshal([]byte(`{"passengers": [
+		{"passenger_id": "pas_1", "cabin": null},
+		{"passenger_id": "pas_2", "cabin": {"name": "business", "marketing_name": "Club World", "amenities": null}},
+		{"passenger_id": "pas_3", "cabin": {"name": "economy", "marketing_name": "Economy", "amenities": {
+			"wifi": {"available": true, "cost": "paid"},
+			"power": {"available": false},
+			"seat": {"pitch": "31", "legroom": "standard"}
+		}}}
+	]}`), &segment)
+	a.NoError(err)
+	a.True(segment.HasWiFi())
+	a.Nil(segment.Passengers[0].Cabin)
+	a.Equal("", segment.Passengers[1].Cabin.Amenities.SeatPitch())
+	a.Equal("31", segment.Passengers[2].Cabin.Amenities.SeatPitch())
+	a.False(segment.Passengers[2].Cabin.Amenities.Power.Available)
+
+	a.False((&Flight{Passengers: segment.Passengers[:2]}).HasWiFi())
+}
+
+func TestOfferDisclosures(t *testing.T) {
+	a := assert.New(t)
+
+	ba := Airline{IATACode: "BA", Name: "British Airways"}
+	aa := Airline{IATACode: "AA", Name: "American Airlines"}
+	offer := &Offer{
+		Slices: []Slice{
+			{Segments: []Flight{{MarketingCarrier: ba, OperatingCarrier: ba}, {MarketingCarrier: ba, OperatingCarrier: aa}}},
+			{Segments: []Flight{{MarketingCarrier: ba, OperatingCarrier: aa}}},
+		},
+	}
+	a.Equal([]string{"Operated by American Airlines"}, offer.Disclosures())
+	a.False(offer.Slices[0].Segments[0].IsCodeshare())
+	a.True(offer.Slices[0].Segments[1].IsCodeshare())
+}
+
+func TestOfferPaymentRequirementPriceGuarantee(t *testing.T) {
+	a := assert.New(t)
+
+	past := DateTime(time.Now().Add(-time.Hour))
+	future := DateTime(time.Now().Add(time.Hour))
+
+	none := OfferPaymentRequirement{}
+	a.False(none.PriceGuaranteeValid())
+	a.Zero(none.PriceGuaranteeTimeLeft())
+
+	expired := OfferPaymentRequirement{PriceGuaranteeExpiresAt: &past}
+	a.False(expired.PriceGuaranteeValid())
+	a.Zero(expired.PriceGuaranteeTimeLeft())
+
+	guaranteed := OfferPaymentRequirement{PriceGuaranteeExpiresAt: &future}
+	a.True(guaranteed.PriceGuaranteeValid())
+	a.InDelta(time.Hour, guaranteed.PriceGuaranteeTimeLeft(), float64(time.Minute))
+
+	var missing *OfferPaymentRequirement
+	a.False(missing.PriceGuaranteeValid())
+}
+
+func TestOfferAmountsConsistent(t *testing.T) {
+	a := assert.New(t)
+
+	offer := Offer{
+		RawTotalAmount: "45.00", RawTotalCurrency: "GBP",
+		RawBaseAmount: "30.20", RawBaseCurrency: "GBP",
+		RawTaxAmount: "14.80", RawTaxCurrency: "GBP",
+	}
+	ok, err := offer.AmountsConsistent()
+	a.NoError(err)
+	a.True(ok)
+
+	offer.RawTaxAmount = "10.00"
+	ok, err = offer.AmountsConsistent()
+	a.NoError(err)
+	a.False(ok)
+
+	offer.RawTaxCurrency = "EUR"
+	_, err = offer.AmountsConsistent()
+	a.Error(err)
+
+	offer.RawBaseAmount = ""
+	_, err = offer.AmountsConsistent()
+	a.Error(err)
+}
+
+func TestOfferMaxConnections(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		Slices: []Slice{
+			{Segments: []Flight{{}}},
+			{Segments: []Flight{{}, {}, {}}},
+		},
+	}
+	a.Equal(0, offer.Slices[0].ConnectionCount())
+	a.Equal(2, offer.Slices[1].ConnectionCount())
+	a.Equal(2, offer.MaxConnections())
+	a.Equal(0, (&Offer{}).MaxConnections())
+	a.Equal(0, (&Slice{}).ConnectionCount())
+}
+
+func TestOfferHoldInfoInstantPayment(t *testing.T) {
+	a := assert.New(t)
+
+	deadline := DateTime(time.Now().Add(time.Hour))
+	offer := &Offer{
+		PaymentRequirements: OfferPaymentRequirement{RequiresInstantPayment: true, PaymentRequiredBy: &deadline},
+	}
+	canHold, until := offer.HoldInfo()
+	a.False(canHold)
+	a.Nil(until)
+}
+
+func TestGetOfferSegmentDisclosures(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/offers/off_00009htYpSCXrwaB9Dn789").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-codeshare.json")
+
+	client := New("duffel_test_123")
+	offer, err := client.GetOffer(context.TODO(), "off_00009htYpSCXrwaB9Dn789")
+	a.NoError(err)
+	if a.Len(offer.Slices, 1) && a.Len(offer.Slices[0].Segments, 2) {
+		a.Equal(
+			[]string{"Operated by American Airlines", "Sold as British Airways flight BA1511"},
+			offer.Slices[0].Segments[0].Disclosures,
+		)
+	}
+	a.Equal(
+		[]string{"Operated by American Airlines", "Sold as British Airways flight BA1511"},
+		offer.Disclosures(),
+	)
+}
@@ -5,21 +5,40 @@
 package duffel
 
 import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// Production is the base URL of the Duffel API. Duffel serves test and live mode from the same host,
+// the mode being selected by the API token (duffel_test_... or duffel_live_...). To test against a mock,
+// pass the URL of an httptest.Server to WithBaseURL or use the duffeltest package.
+const Production = "https://api.duffel.com"
+
 // WithAPIToken sets the API host to the default Duffel production host.
 func WithDefaultAPI() Option {
-	return func(c *Options) {
-		c.Host = "https://api.duffel.com"
-	}
+	return WithBaseURL(Production)
 }
 
 // WithHost allows you to specify the Duffel API host to use for making requests.
+//
+// Deprecated: use WithBaseURL, which it is an alias of.
 func WithHost(host string) Option {
+	return WithBaseURL(host)
+}
+
+// WithBaseURL sends requests to another base URL than the Duffel API, such as a self-hosted proxy
+// or a mock server. The base URL may have a path, which prefixes the path of every request,
+// e.g. WithBaseURL("https://proxy.internal/duffel") sends GetOrder to https://proxy.internal/duffel/air/orders/{id}.
+func WithBaseURL(baseURL string) Option {
 	return func(c *Options) {
-		c.Host = host
+		c.Host = baseURL
 	}
 }
 
@@ -30,7 +49,25 @@ func WithAPIVersion(version string) Option {
 	}
 }
 
-// WithUserAgent allows you to specify a custom user agent string to use for making requests.
+// WithVersionDriftHandler registers a hook called when the API responds with another
+// "Duffel-Version" than the one requested, to detect schema drift before it breaks decoding.
+func WithVersionDriftHandler(handler VersionDriftFunc) Option {
+	return func(c *Options) {
+		c.OnVersionDrift = handler
+	}
+}
+
+// WithStrictAPIVersion fails requests with ErrAPIVersionMismatch when the API responds with
+// another "Duffel-Version" than the one requested, instead of decoding the response.
+func WithStrictAPIVersion() Option {
+	return func(c *Options) {
+		c.StrictVersion = true
+	}
+}
+
+// WithUserAgent allows you to specify a custom user agent string to use for making requests,
+// e.g. "acme-booking/3.1" to identify your integration. The library and its version are still
+// identified by the X-Duffel-Client header.
 func WithUserAgent(ua string) Option {
 	return func(c *Options) {
 		c.UserAgent = ua
@@ -38,21 +75,212 @@ func WithUserAgent(ua string) Option {
 }
 
 // WithHTTPClient allows you to specify a custom http.Client to use for making requests.
-// This is useful if you want to use a custom transport or proxy.
+// This is useful if you want to use a custom transport or proxy. A nil client keeps the default.
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *Options) {
-		c.HttpDoer = client
+		if client != nil {
+			c.HttpDoer = client
+		}
+	}
+}
+
+// WithTransportConfig tunes the connection pool of the default HTTP transport.
+// It has no effect when a custom client is supplied with WithHTTPClient.
+//
+// High-volume integrators will usually want more idle connections per host than the
+// net/http default of 2, for example WithTransportConfig(100, 50, 90*time.Second).
+func WithTransportConfig(maxIdleConns, maxConnsPerHost int, idleTimeout time.Duration) Option {
+	return func(c *Options) {
+		t := c.transportConfig()
+		t.MaxIdleConns = maxIdleConns
+		t.MaxConnsPerHost = maxConnsPerHost
+		t.IdleConnTimeout = idleTimeout
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept to the Duffel API host by the
+// default HTTP transport. It has no effect when a custom client is supplied with WithHTTPClient.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(c *Options) {
+		c.transportConfig().MaxIdleConnsPerHost = n
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the default HTTP transport.
+// It has no effect when a custom client is supplied with WithHTTPClient.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Options) {
+		c.transportConfig().TLSClientConfig = config
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 on the default HTTP transport. It is enabled by default;
+// disabling it spreads requests over several HTTP/1.1 connections instead of multiplexing them.
+// It has no effect when a custom client is supplied with WithHTTPClient.
+func WithHTTP2(enabled bool) Option {
+	return func(c *Options) {
+		c.transportConfig().DisableHTTP2 = !enabled
+	}
+}
+
+func (c *Options) transportConfig() *TransportConfig {
+	if c.Transport == nil {
+		c.Transport = &TransportConfig{}
+	}
+	return c.Transport
+}
+
+// WithRateLimitThrottling paces requests according to the Ratelimit headers of the latest response,
+// spreading the remaining quota until the rate limit resets and waiting for the reset once it is
+// exhausted, so that large ListOffers or ListOrders syncs do not run into 429 responses.
+// The pacing is shared by all requests made with the client using the same API token, each token
+// set with WithToken or a TokenProvider having its own rate limit.
+func WithRateLimitThrottling() Option {
+	return func(c *Options) {
+		c.Throttle = true
+	}
+}
+
+// WithBackgroundReserve throttles requests like WithRateLimitThrottling, delaying requests made with
+// PriorityBackground until the next rate limit window once only reserve requests remain in the current
+// one, so that a background sync cannot starve user-facing requests:
+//
+//	ctx = duffel.WithPriority(ctx, duffel.PriorityBackground)
+//	iter := client.ListOrders(ctx)
+func WithBackgroundReserve(reserve int) Option {
+	return func(c *Options) {
+		c.Throttle = true
+		c.BackgroundReserve = reserve
+	}
+}
+
+// WithRetries retries idempotent requests failing with 429, a 5xx status or a network error,
+// making at most maxAttempts attempts. The delay starts at backoff and doubles with every attempt,
+// with jitter, and is at least the Retry-After delay requested by the API.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Options) {
+		c.Retry = &RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+		}
+	}
+}
+
+// WithIdempotencyKeys sends a generated "Idempotency-Key" header with the requests whose effect must
+// not be repeated: CreateOrder, CreatePayment, ConfirmOrderChange and ConfirmOrderCancellation.
+// Retrying them after a network timeout, manually with the same key given by WithIdempotencyKey or
+// automatically with WithRetries, then cannot book or charge twice.
+func WithIdempotencyKeys() Option {
+	return func(c *Options) {
+		c.IdempotencyKeys = true
+	}
+}
+
+// WithNoOffersError makes CreateOfferRequestWithOffers return the offer request along with ErrNoOffers
+// when no airline returned an offer, so that "no flights" can be told apart from a failed request
+// with errors.Is instead of checking the number of offers.
+func WithNoOffersError() Option {
+	return func(c *Options) {
+		c.NoOffersError = true
+	}
+}
+
+// WithTracerProvider emits an OpenTelemetry span for every API call, with the method, path, status,
+// Duffel request ID and rate limit as attributes. Retries of a call are part of its span.
+// A nil provider disables tracing.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Options) {
+		if provider == nil {
+			provider = noop.NewTracerProvider()
+		}
+		c.TracerProvider = provider
+	}
+}
+
+// WithTokenProvider resolves the API token of every request with provider instead of using the token
+// given to New, e.g. to look up the token of the tenant a request is made for in its context.
+// A token set for a call with WithToken takes precedence.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Options) {
+		c.TokenProvider = provider
+	}
+}
+
+// WithTokenSource takes the API token of every request from source instead of the token given to New,
+// so that the token can rotate while the client is in use:
+//
+//	client := duffel.New("", duffel.WithTokenSource(duffel.ReuseTokenSource(secrets, 5*time.Minute)))
+//
+// It replaces any TokenProvider; a token set for a call with WithToken takes precedence.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Options) {
+		c.TokenProvider = func(context.Context) (string, error) {
+			return source.Token()
+		}
+	}
+}
+
+// WithCircuitBreaker sends every request through the circuit breaker, such as a *gobreaker.CircuitBreaker,
+// so that requests fail fast with ErrCircuitOpen while the API is failing consistently, instead of
+// queueing up. Network errors and 5xx responses count as failures; other errors, such as validation
+// errors or rate limiting, count as successes. Requests rejected by the breaker are not retried.
+func WithCircuitBreaker(breaker CircuitBreaker) Option {
+	return func(c *Options) {
+		c.CircuitBreaker = breaker
+	}
+}
+
+// WithMiddleware adds middleware wrapping the sending of every request to the API, to inspect or modify
+// requests and responses for all endpoints. Middleware is applied in the order given, the first being
+// the outermost, and sees every attempt of retried requests:
+//
+//	duffel.WithMiddleware(func(next duffel.RoundTripFunc) duffel.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Tenant", tenant)
+//			return next(req)
+//		}
+//	})
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Options) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// WithLogger logs requests to the API as structured records: responses and rate limits at debug level,
+// throttling and retries at info level, and failed requests, exhausted rate limits and API version
+// mismatches at warning level. Unlike WithDebug, request and response bodies are not logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Options) {
+		c.Logger = logger
 	}
 }
 
 // WithDebug enables debug logging of requests and responses.
-// DO NOT USE IN PRODUCTION.
+// Credentials, card details, passenger contact details and identity documents are redacted,
+// but the output still holds passenger names and itineraries. DO NOT USE IN PRODUCTION.
 func WithDebug() Option {
 	return func(c *Options) {
 		c.Debug = true
 	}
 }
 
+// WithDebugWriter enables the debug output of WithDebug, writing it to w instead of os.Stdout,
+// e.g. a file or a log pipeline. Use the SetDebug method of Debugger to turn it off and on at runtime.
+func WithDebugWriter(w io.Writer) Option {
+	return func(c *Options) {
+		c.Debug = true
+		c.DebugWriter = w
+	}
+}
+
+// WithRequestCapture registers a hook receiving every request just before it is sent,
+// with the Authorization header redacted. Use it to replay a misbehaving call.
+func WithRequestCapture(capture RequestCaptureFunc) Option {
+	return func(c *Options) {
+		c.RequestCapture = capture
+	}
+}
+
 // WithTimeout sets the deadline timeout.
 // The Duffel API recommends at least 130 seconds for order creation endpoints.
 // Default is 130 seconds.
@@ -61,3 +289,22 @@ func WithTimeout(d time.Duration) Option {
 		c.Timeout = d
 	}
 }
+
+// WithClock sets the clock used to pace requests against the rate limit.
+// This is intended for tests that need to observe or skip rate limit waits.
+func WithClock(clock Clock) Option {
+	return func(c *Options) {
+		c.Clock = clock
+	}
+}
+
+// WithCassette records interactions with the Duffel API to the file at path and replays them offline,
+// depending on mode, e.g. to test a complete offer to order flow in CI without a live token.
+// Requests are matched on method, path with query and JSON body; the API token is never recorded.
+// Card details, tokens, passenger names, contact details, birth dates and identity documents are
+// redacted from the recorded bodies, and only the response headers the client reads are kept.
+func WithCassette(path string, mode RecordMode) Option {
+	return func(c *Options) {
+		c.cassette = &cassetteTransport{path: path, mode: mode}
+	}
+}
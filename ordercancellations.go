@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/bojanz/currency"
 )
@@ -37,6 +38,9 @@ type (
 		ID                string `json:"id"`
 		IssuedOn          Date   `json:"issued_on"`
 		PassengerID       string `json:"passenger_id"`
+		// ExpiresAt is when the credit lapses. Duffel does not return it for every airline;
+		// when nil, the airline's validity policy applies, typically counted from IssuedOn.
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
 	}
 
 	// OrderCancellationRequest is response from the OrderCancellation API.
@@ -85,6 +89,7 @@ func (a *API) ConfirmOrderCancellation(ctx context.Context, orderCancellationID
 
 	return newRequestWithAPI[EmptyPayload, OrderCancellation](a).
 		Post(fmt.Sprintf("/air/order_cancellations/%s/actions/confirm", orderCancellationID), nil).
+		Idempotent().
 		Single(ctx)
 }
 
@@ -119,6 +124,23 @@ func (o *OrderCancellation) RefundAmount() currency.Amount {
 	return amount
 }
 
+// IsExpired returns true if the credit has an expiry and it has passed.
+// It returns false when Duffel did not return an expiry.
+func (c *AirlineCredit) IsExpired() bool {
+	return c.ExpiresAt != nil && !time.Now().Before(*c.ExpiresAt)
+}
+
+// ExpiresWithin returns true if the credit has not expired yet but will within d from now,
+// which is when customers should be reminded to use it. Expired credits are excluded; see IsExpired.
+func (c *AirlineCredit) ExpiresWithin(d time.Duration) bool {
+	return c.ExpiresAt != nil && !c.IsExpired() && time.Now().Add(d).After(*c.ExpiresAt)
+}
+
+// Age returns the time elapsed since the credit was issued, for credits without an expiry.
+func (c *AirlineCredit) Age() time.Duration {
+	return time.Since(time.Time(c.IssuedOn))
+}
+
 func (l ListOrderCancellationParams) Encode(v url.Values) error {
 	if l.OrderID != "" {
 		v.Set("order_id", l.OrderID)
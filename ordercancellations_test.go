@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/segmentio/encoding/json"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -80,3 +81,24 @@ func TestGetOrderCancellation(t *testing.T) {
 	a.NotNil(data)
 	a.Equal("90.80 GBP", data.RefundAmount().String())
 }
+
+func TestAirlineCreditExpiry(t *testing.T) {
+	a := assert.New(t)
+
+	var credit AirlineCredit
+	a.NoError(json.Unmarshal([]byte(`{"id": "acd_123", "issued_on": "2024-01-01"}`), &credit))
+	a.Nil(credit.ExpiresAt)
+	a.False(credit.IsExpired())
+	a.False(credit.ExpiresWithin(24 * time.Hour))
+	a.Greater(credit.Age(), 24*time.Hour)
+
+	past := time.Now().Add(-time.Hour)
+	a.True((&AirlineCredit{ExpiresAt: &past}).IsExpired())
+	a.False((&AirlineCredit{ExpiresAt: &past}).ExpiresWithin(24 * time.Hour))
+
+	soon := time.Now().Add(time.Hour)
+	credit = AirlineCredit{ExpiresAt: &soon}
+	a.False(credit.IsExpired())
+	a.True(credit.ExpiresWithin(24 * time.Hour))
+	a.False(credit.ExpiresWithin(time.Minute))
+}
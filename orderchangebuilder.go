@@ -0,0 +1,68 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OrderChangeRequestBuilder assembles the OrderChangeRequestParams for CreateOrderChangeRequest.
+// The zero value is ready to use.
+type OrderChangeRequestBuilder struct {
+	add    []SliceAdd
+	remove []SliceRemove
+}
+
+// NewOrderChangeRequestBuilder returns an empty OrderChangeRequestBuilder.
+func NewOrderChangeRequestBuilder() *OrderChangeRequestBuilder {
+	return &OrderChangeRequestBuilder{}
+}
+
+// RemoveSlice removes the slice with the given ID from the order.
+func (b *OrderChangeRequestBuilder) RemoveSlice(sliceID string) *OrderChangeRequestBuilder {
+	b.remove = append(b.remove, SliceRemove{SliceID: sliceID})
+	return b
+}
+
+// AddSlice searches for a new slice between the origin and destination IATA codes on the given date.
+func (b *OrderChangeRequestBuilder) AddSlice(
+	origin, destination string, departureDate Date, cabinClass CabinClass,
+) *OrderChangeRequestBuilder {
+	b.add = append(
+		b.add, SliceAdd{
+			Origin:        origin,
+			Destination:   destination,
+			DepartureDate: departureDate,
+			CabinClass:    cabinClass,
+		},
+	)
+	return b
+}
+
+// Build returns the params to change the given order.
+// It fails if no slice is added or removed, or if an added slice has no origin or destination.
+func (b *OrderChangeRequestBuilder) Build(orderID string) (OrderChangeRequestParams, error) {
+	if !strings.HasPrefix(orderID, orderIDPrefix) {
+		return OrderChangeRequestParams{}, fmt.Errorf("orderID should begin with %s", orderIDPrefix)
+	}
+	if len(b.add) == 0 && len(b.remove) == 0 {
+		return OrderChangeRequestParams{}, fmt.Errorf("order change request should add or remove at least one slice")
+	}
+	for _, slice := range b.add {
+		if slice.Origin == "" || slice.Destination == "" {
+			return OrderChangeRequestParams{}, fmt.Errorf("added slice should have an origin and a destination")
+		}
+	}
+
+	return OrderChangeRequestParams{
+		OrderID: orderID,
+		Slices: SliceChange{
+			Add:    slices.Clone(b.add),
+			Remove: slices.Clone(b.remove),
+		},
+	}, nil
+}
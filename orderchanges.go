@@ -171,6 +171,7 @@ func (a *API) ConfirmOrderChange(
 	return newRequestWithAPI[PaymentCreateInput, OrderChange](a).
 		Postf("/air/order_changes/%s/actions/confirm", orderChangeRequestID).
 		Body(&payment).
+		Idempotent().
 		Single(ctx)
 }
 
@@ -206,6 +207,40 @@ func (a *API) ListOrderChangeOffers(
 		Iter(ctx)
 }
 
+// DowngradesFlexibility returns true if the change offer's conditions are less flexible than the
+// original order's for refunds or changes: the modification is no longer allowed, is no longer free,
+// or has a higher penalty in the same currency. Unknown conditions on either side are not compared.
+func (o *OrderChangeOffer) DowngradesFlexibility(original Conditions) bool {
+	return conditionDowngraded(original.RefundBeforeDeparture, o.Conditions.RefundBeforeDeparture) ||
+		conditionDowngraded(original.ChangeBeforeDeparture, o.Conditions.ChangeBeforeDeparture)
+}
+
+var changePolicyFlexibility = map[ChangePolicy]int{
+	ChangeNotAllowed:  0,
+	ChangeWithPenalty: 1,
+	ChangeFree:        2,
+}
+
+func conditionDowngraded(original, updated *ChangeCondition) bool {
+	before, ok := changePolicyFlexibility[original.Policy()]
+	if !ok {
+		return false
+	}
+	after, ok := changePolicyFlexibility[updated.Policy()]
+	if !ok {
+		return false
+	}
+	if after != before {
+		return after < before
+	}
+
+	if updated.Policy() == ChangeWithPenalty {
+		cmp, err := updated.PenaltyAmount().Cmp(*original.PenaltyAmount())
+		return err == nil && cmp > 0
+	}
+	return false
+}
+
 var _ OrderChangeClient = (*API)(nil)
 
 func validateID(id, prefix string) error {
@@ -122,3 +122,71 @@ func TestCreatePendingOrderChange(t *testing.T) {
 	a.Equal("ocr_0000A3tQSmKyqOrcySrGbo", data.ID)
 	a.Equal("ord_0000A3tQcCRZ9R8OY0QlxA", data.OrderID)
 }
+
+func TestOrderChangeRequestBuilder(t *testing.T) {
+	a := assert.New(t)
+	departure := Date(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+
+	params, err := NewOrderChangeRequestBuilder().
+		RemoveSlice("sli_123").
+		AddSlice("LHR", "JFK", departure, CabinClassEconomy).
+		Build("ord_123")
+	a.NoError(err)
+	a.Equal(
+		OrderChangeRequestParams{
+			OrderID: "ord_123",
+			Slices: SliceChange{
+				Add: []SliceAdd{
+					{Origin: "LHR", Destination: "JFK", DepartureDate: departure, CabinClass: CabinClassEconomy},
+				},
+				Remove: []SliceRemove{{SliceID: "sli_123"}},
+			},
+		}, params,
+	)
+
+	_, err = NewOrderChangeRequestBuilder().Build("ord_123")
+	a.Error(err)
+
+	_, err = NewOrderChangeRequestBuilder().RemoveSlice("sli_123").Build("123")
+	a.Error(err)
+
+	_, err = NewOrderChangeRequestBuilder().AddSlice("", "JFK", departure, CabinClassEconomy).Build("ord_123")
+	a.Error(err)
+}
+
+func TestOrderChangeOfferDowngradesFlexibility(t *testing.T) {
+	a := assert.New(t)
+	condition := func(allowed bool, amount string) *ChangeCondition {
+		if amount == "" {
+			return &ChangeCondition{Allowed: allowed}
+		}
+		currency := "GBP"
+		return &ChangeCondition{Allowed: allowed, RawPenaltyAmount: &amount, RawPenaltyCurrency: &currency}
+	}
+
+	original := Conditions{
+		RefundBeforeDeparture: condition(true, "50.00"),
+		ChangeBeforeDeparture: condition(true, "0.00"),
+	}
+
+	same := &OrderChangeOffer{Conditions: original}
+	a.False(same.DowngradesFlexibility(original))
+
+	nonRefundable := &OrderChangeOffer{
+		Conditions: Conditions{RefundBeforeDeparture: condition(false, ""), ChangeBeforeDeparture: condition(true, "0.00")},
+	}
+	a.True(nonRefundable.DowngradesFlexibility(original))
+
+	paidChanges := &OrderChangeOffer{
+		Conditions: Conditions{RefundBeforeDeparture: condition(true, "50.00"), ChangeBeforeDeparture: condition(true, "25.00")},
+	}
+	a.True(paidChanges.DowngradesFlexibility(original))
+
+	higherPenalty := &OrderChangeOffer{
+		Conditions: Conditions{RefundBeforeDeparture: condition(true, "75.00"), ChangeBeforeDeparture: condition(true, "0.00")},
+	}
+	a.True(higherPenalty.DowngradesFlexibility(original))
+
+	unknown := &OrderChangeOffer{}
+	a.False(unknown.DowngradesFlexibility(original))
+}
@@ -6,8 +6,13 @@ package duffel
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/bojanz/currency"
@@ -16,6 +21,21 @@ import (
 
 const orderIDPrefix = "ord_"
 
+// ErrServicesNotAllowed is returned by AddServiceToOrder when services cannot be added to the order;
+// see Order.CanAddServices.
+var ErrServicesNotAllowed = fmt.Errorf("duffel: services cannot be added to this order")
+
+// maxConcurrentOrderUpdates bounds the orders updated in parallel by UpdateOrdersMetadata.
+const maxConcurrentOrderUpdates = 5
+
+const (
+	// ChangeUnknown means the airline did not say whether the modification is allowed or at what penalty.
+	ChangeUnknown     ChangePolicy = "unknown"
+	ChangeNotAllowed  ChangePolicy = "not_allowed"
+	ChangeFree        ChangePolicy = "free"
+	ChangeWithPenalty ChangePolicy = "with_penalty"
+)
+
 type (
 	ListOrdersSort string
 
@@ -47,6 +67,7 @@ type (
 		Content                 OrderContent                `json:"content"`
 		OfferID                 string                      `json:"offer_id"`
 		Type                    OrderType                   `json:"type"`
+		TotalEmissionsKg        *string                     `json:"total_emissions_kg,omitempty"`
 		IsPendingConfirmation   bool                        `json:"-"`
 		IsAwaitingCreation      bool                        `json:"-"`
 		// TODO: Users // preview - slice of string ids representing users allowed to manage this order
@@ -61,16 +82,28 @@ type (
 		ChangeBeforeDeparture *ChangeCondition `json:"change_before_departure,omitempty"`
 	}
 
+	// FXFunc converts an amount to the given currency code, e.g. using rates from an FX provider.
+	FXFunc func(amount currency.Amount, currencyCode string) (currency.Amount, error)
+
+	// ChangePolicy summarises a ChangeCondition, e.g. to render flexibility badges.
+	ChangePolicy string
+
 	ChangeCondition struct {
 		Allowed            bool    `json:"allowed"`
 		RawPenaltyAmount   *string `json:"penalty_amount,omitempty"`
 		RawPenaltyCurrency *string `json:"penalty_currency,omitempty"`
 	}
 
+	// IssuedDocument is a document issued by the airline for an order, such as an electronic ticket.
+	//
+	// Duffel does not expose a download endpoint for issued documents, so there is no PDF to fetch.
+	// The UniqueIdentifier (e.g. the e-ticket number) is the reference to share with the traveller,
+	// who can use it alongside the booking reference to retrieve the itinerary from the airline.
 	IssuedDocument struct {
-		PassengerIDs     []string           `json:"passenger_ids"`
-		Type             IssuedDocumentType `json:"type"`
-		UniqueIdentifier string             `json:"unique_identifier"`
+		PassengerIDs []string           `json:"passenger_ids"`
+		Type         IssuedDocumentType `json:"type"`
+		// The identifier of the document, e.g. the electronic ticket number.
+		UniqueIdentifier string `json:"unique_identifier"`
 	}
 
 	// NOTE: If you receive a 500 Internal Server Error when trying to create an order,
@@ -151,11 +184,18 @@ type (
 		Type string `json:"type"`
 	}
 
+	// PassengerManifestEntry is a passenger of an order along with the services booked for them.
+	PassengerManifestEntry struct {
+		Passenger OrderPassenger
+		// Services booked for this passenger, such as seats and bags.
+		Services []Service
+	}
+
 	// OrderUpdateParams is used as the input to UpdateOrder.
 	// Only certain order fields are updateable.
 	// Each field that can be updated is detailed in the `OrderUpdateParams` object.
 	OrderUpdateParams struct {
-		Metadata map[string]any
+		Metadata map[string]any `json:"metadata"`
 	}
 
 	ListOrdersParams struct {
@@ -185,14 +225,14 @@ type (
 
 		// Filters the returned orders by departure datetime.
 		// Orders will be included if any of their segments matches the given criteria
-		DepartingAt *TimeFilter `url:"departing_at,omitempty"`
+		DepartingAt *TimeFilter `url:"-"`
 
 		// Filters the returned orders by arrival datetime.
 		// Orders will be included if any of their segments matches the given criteria.
-		ArrivingAt *TimeFilter `url:"arriving_at,omitempty"`
+		ArrivingAt *TimeFilter `url:"-"`
 
 		// Filters the returned orders by creation datetime.
-		CreatedAt *TimeFilter `url:"created_at,omitempty"`
+		CreatedAt *TimeFilter `url:"-"`
 
 		// Orders will be included if any of their passengers matches any of the given names.
 		// Matches are case-insensitive, and include partial matches.
@@ -283,6 +323,55 @@ type (
 			ctx context.Context, params ...ListAirlineInitiatedChangesParams,
 		) ([]*AirlineInitiatedChanges, error)
 	}
+
+	// OrderMetadataUpdater is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.OrderMetadataUpdater).
+	OrderMetadataUpdater interface {
+		// UpdateOrdersMetadata Merge metadata updates into many orders.
+		UpdateOrdersMetadata(ctx context.Context, updates map[string]map[string]any) (map[string]*Order, []error)
+	}
+
+	// OrderPreflighter is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.OrderPreflighter).
+	OrderPreflighter interface {
+		// PreflightOrder Validate an order input against the current offer without creating it.
+		PreflightOrder(ctx context.Context, input CreateOrderInput) (*Offer, error)
+	}
+
+	// ExpiredHoldOrderLister is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.ExpiredHoldOrderLister).
+	ExpiredHoldOrderLister interface {
+		// ExpiredHoldOrders List hold orders whose payment deadline has passed.
+		ExpiredHoldOrders(ctx context.Context) ([]*Order, error)
+	}
+
+	// DuplicateOrderFinder is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.DuplicateOrderFinder).
+	DuplicateOrderFinder interface {
+		// FindExistingOrders Find orders that may duplicate a new booking.
+		FindExistingOrders(ctx context.Context, passengerNames []string, departing *TimeFilter) ([]*Order, error)
+	}
+
+	// CancelForAnyReasonClient is implemented by the client returned by New. It is kept out of Duffel so that
+	// other implementations, such as mocks, need not provide it: use client.(duffel.CancelForAnyReasonClient).
+	CancelForAnyReasonClient interface {
+		// AddCancelForAnyReason Add a cancel for any reason service to an order.
+		AddCancelForAnyReason(ctx context.Context, orderID, serviceID string, payment PaymentCreateInput) (*Order, error)
+	}
+
+	// OrderResumer is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.OrderResumer).
+	OrderResumer interface {
+		// ListOrdersAfter List orders starting from a cursor.
+		ListOrdersAfter(ctx context.Context, cursor string, params ...ListOrdersParams) *Iter[Order]
+	}
+
+	// OrderWaiter is implemented by the client returned by New. It is kept out of Duffel so that other
+	// implementations, such as mocks, need not provide it: use client.(duffel.OrderWaiter).
+	OrderWaiter interface {
+		// WaitForOrder Poll an order until a condition is met.
+		WaitForOrder(ctx context.Context, id string, ready func(*Order) bool, opts ...WaitOption) (*Order, error)
+	}
 )
 
 const (
@@ -308,11 +397,57 @@ const (
 	OrderContentSelfManaged = OrderContent("self_managed")
 )
 
+// ComputeRequiredPayment returns the amount to pay for the order: the total of the selected offer
+// plus the total of each service in Services, priced from the offer's available services.
+// The offer must be the selected offer, fetched with its available services.
+func (in *CreateOrderInput) ComputeRequiredPayment(offer *Offer) (currency.Amount, error) {
+	if offer == nil || !slices.Contains(in.SelectedOffers, offer.ID) {
+		return currency.Amount{}, fmt.Errorf("offer is not one of the selected offers")
+	}
+
+	total, err := currency.NewAmount(offer.RawTotalAmount, offer.RawTotalCurrency)
+	if err != nil {
+		return currency.Amount{}, err
+	}
+
+	for _, service := range in.Services {
+		index := slices.IndexFunc(
+			offer.AvailableServices, func(s AvailableService) bool {
+				return s.ID == service.ID
+			},
+		)
+		if index == -1 {
+			return currency.Amount{}, fmt.Errorf("service %s is not available on offer %s", service.ID, offer.ID)
+		}
+		if service.Quantity < 1 {
+			return currency.Amount{}, fmt.Errorf("service %s should have a positive quantity", service.ID)
+		}
+
+		available := offer.AvailableServices[index]
+		price, err := currency.NewAmount(available.RawTotalAmount, available.RawTotalCurrency)
+		if err != nil {
+			return currency.Amount{}, err
+		}
+		price, err = price.Mul(strconv.Itoa(service.Quantity))
+		if err != nil {
+			return currency.Amount{}, err
+		}
+		total, err = total.Add(price)
+		if err != nil {
+			return currency.Amount{}, err
+		}
+	}
+	return total, nil
+}
+
 // CreateOrder creates a new order.
+//
+// The input only holds offer IDs, so it cannot be checked for partial offers (see Offer.IsPartial),
+// which Duffel rejects: use CreateOrderForOffer when the offer is at hand.
 func (a *API) CreateOrder(ctx context.Context, input CreateOrderInput) (*Order, error) {
 	order, statusCode, err := newRequestWithAPI[CreateOrderInput, Order](a).Post(
 		"/air/orders", &input,
-	).SingleWithResponse(ctx)
+	).Idempotent().SingleWithResponse(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -325,11 +460,86 @@ func (a *API) CreateOrder(ctx context.Context, input CreateOrderInput) (*Order,
 	return order, nil
 }
 
+// CreateOrderForOffer creates a new order for the given offer, which is selected in the input. It returns
+// ErrPartialOffer without sending anything when the offer is partial, and an error when the input already
+// selects other offers.
+func (a *API) CreateOrderForOffer(ctx context.Context, offer *Offer, input CreateOrderInput) (*Order, error) {
+	if offer == nil {
+		return nil, fmt.Errorf("duffel: no offer to create an order for")
+	}
+	if offer.IsPartial() {
+		return nil, fmt.Errorf("%w: offer %s", ErrPartialOffer, offer.ID)
+	}
+	if len(input.SelectedOffers) > 0 && !slices.Equal(input.SelectedOffers, []string{offer.ID}) {
+		return nil, fmt.Errorf("duffel: input selects offers %v instead of offer %s", input.SelectedOffers, offer.ID)
+	}
+	input.SelectedOffers = []string{offer.ID}
+	return a.CreateOrder(ctx, input)
+}
+
 // UpdateOrder updates an existing order with update-able fields (mostly metadata).
 func (a *API) UpdateOrder(ctx context.Context, id string, params OrderUpdateParams) (*Order, error) {
 	return newRequestWithAPI[OrderUpdateParams, Order](a).Patch("/air/orders/"+id, &params).Single(ctx)
 }
 
+// UpdateOrdersMetadata merges metadata updates, keyed by order ID, into the current metadata of each order
+// with Metadata.Merge, updating at most maxConcurrentOrderUpdates orders at a time. Duffel replaces the whole
+// metadata on update, so each order is retrieved first.
+//
+// It returns the updated orders keyed by ID, and an error for each order that could not be updated.
+func (a *API) UpdateOrdersMetadata(ctx context.Context, updates map[string]map[string]any) (
+	map[string]*Order, []error,
+) {
+	ids := make([]string, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		orders = make(map[string]*Order, len(ids))
+		errs   []error
+	)
+	record := func(id string, order *Order, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("order %s: %w", id, err))
+			return
+		}
+		orders[id] = order
+	}
+
+	sem := make(chan struct{}, maxConcurrentOrderUpdates)
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			record(id, nil, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			order, err := a.GetOrder(ctx, id)
+			if err != nil {
+				record(id, nil, err)
+				return
+			}
+			order, err = a.UpdateOrder(ctx, id, OrderUpdateParams{Metadata: order.Metadata.Merge(updates[id])})
+			record(id, order, err)
+		}()
+	}
+	wg.Wait()
+
+	return orders, errs
+}
+
 // GetOrder returns a single order by ID.
 func (a *API) GetOrder(ctx context.Context, id string) (*Order, error) {
 	return newRequestWithAPI[EmptyPayload, Order](a).Get("/air/orders/" + id).Single(ctx)
@@ -343,6 +553,58 @@ func (a *API) ListOrders(ctx context.Context, params ...ListOrdersParams) *Iter[
 		Iter(ctx)
 }
 
+// FindExistingOrders returns the orders with a passenger matching any of the given names and, when
+// departing is set, a segment departing within it. Use it to warn about a possible duplicate booking
+// before calling CreateOrder.
+func (a *API) FindExistingOrders(ctx context.Context, passengerNames []string, departing *TimeFilter) (
+	[]*Order, error,
+) {
+	if len(passengerNames) == 0 {
+		return nil, fmt.Errorf("at least one passenger name is required")
+	}
+
+	return Collect(
+		a.ListOrders(
+			ctx, ListOrdersParams{
+				PassengerNames: passengerNames,
+				DepartingAt:    departing,
+			},
+		),
+	)
+}
+
+// ExpiredHoldOrders returns the orders awaiting payment whose payment deadline has passed.
+// The airline cancels such orders, so use it to clean up records and notify customers whose hold lapsed.
+// Duffel has no filter on the deadline, so every order awaiting payment is listed and checked client-side.
+func (a *API) ExpiredHoldOrders(ctx context.Context) ([]*Order, error) {
+	iter := a.ListOrders(
+		ctx, ListOrdersParams{
+			AwaitingPayment: true,
+			Sort:            ListOrdersSortPaymentRequiredByAsc,
+		},
+	)
+
+	expired := make([]*Order, 0)
+	for iter.Next() {
+		if order := iter.Current(); order.HoldExpired() {
+			expired = append(expired, order)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// ListOrdersAfter returns a list of orders starting from the given cursor, as returned by Iter.After.
+// Use it to resume a long iteration over ListOrders instead of restarting it.
+func (a *API) ListOrdersAfter(ctx context.Context, cursor string, params ...ListOrdersParams) *Iter[Order] {
+	return newRequestWithAPI[ListOrdersParams, Order](a).
+		Get("/air/orders").
+		WithParams(normalizeParams(params)...).
+		IterAfter(ctx, cursor)
+}
+
 // ListOrderServices returns a list of available services for an order.
 func (a *API) ListOrderServices(ctx context.Context, id string) ([]*AvailableService, error) {
 	return newRequestWithAPI[EmptyPayload, AvailableService](a).
@@ -356,6 +618,60 @@ func (a *API) AddOrderService(ctx context.Context, id string, input AddOrderServ
 		Single(ctx)
 }
 
+// AddServiceToOrder adds a service to the given order with AddOrderService. It returns ErrServicesNotAllowed
+// without sending anything when the order is self-managed or cancelled; see Order.CanAddServices.
+func (a *API) AddServiceToOrder(ctx context.Context, order *Order, input AddOrderServiceInput) (*Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("duffel: no order to add services to")
+	}
+	if !order.CanAddServices() {
+		return nil, fmt.Errorf("%w: order %s", ErrServicesNotAllowed, order.ID)
+	}
+	return a.AddOrderService(ctx, order.ID, input)
+}
+
+// ConditionDeadlinePassed returns true if the modification described by the before-departure condition
+// can no longer be made, e.g. to disable a refund button: when the condition is missing or not allowed,
+// or once departure has been reached according to the client's clock.
+// Duffel's before-departure conditions do not carry a separate deadline: they apply until the
+// departure of the slice or order they describe.
+func (a *API) ConditionDeadlinePassed(condition *ChangeCondition, departure time.Time) bool {
+	if condition == nil || !condition.Allowed {
+		return true
+	}
+	return !a.options.Clock.Now().Before(departure)
+}
+
+// AddCancelForAnyReason adds a cancel for any reason service to an order, after checking that the
+// service is available on the order and is of type cancel_for_any_reason.
+func (a *API) AddCancelForAnyReason(
+	ctx context.Context, orderID, serviceID string, payment PaymentCreateInput,
+) (*Order, error) {
+	services, err := a.ListOrderServices(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(
+		services, func(s *AvailableService) bool {
+			return s.ID == serviceID
+		},
+	)
+	if index == -1 {
+		return nil, fmt.Errorf("service %s is not available on order %s", serviceID, orderID)
+	}
+	if ServiceType(services[index].Type) != ServiceTypeCancel {
+		return nil, fmt.Errorf("service %s is not a cancel for any reason service", serviceID)
+	}
+
+	return a.AddOrderService(
+		ctx, orderID, AddOrderServiceInput{
+			AddServices: []ServiceCreateInput{{ID: serviceID, Quantity: 1}},
+			Payment:     payment,
+		},
+	)
+}
+
 // UpdateAirlineInitiatedChange updates an airline-initiated change.
 func (a *API) UpdateAirlineInitiatedChange(
 	ctx context.Context, id string, input UpdateAirlineInitiatedChangeInput,
@@ -411,7 +727,11 @@ func (o *Order) TotalAmount() currency.Amount {
 	return amount
 }
 
+// PenaltyAmount returns the penalty of the condition, or nil if there is none or it cannot be parsed.
 func (c *ChangeCondition) PenaltyAmount() *currency.Amount {
+	if c == nil {
+		return nil
+	}
 	if c.RawPenaltyAmount != nil && c.RawPenaltyCurrency != nil {
 		amount, err := currency.NewAmount(*c.RawPenaltyAmount, *c.RawPenaltyCurrency)
 		if err != nil {
@@ -423,6 +743,318 @@ func (c *ChangeCondition) PenaltyAmount() *currency.Amount {
 	return nil
 }
 
+// PenaltyAmountIn returns the penalty converted to the given currency code using fx.
+// It returns nil without error when the condition has no penalty, and skips fx when the
+// penalty is already in the requested currency.
+func (c *ChangeCondition) PenaltyAmountIn(currencyCode string, fx FXFunc) (*currency.Amount, error) {
+	penalty := c.PenaltyAmount()
+	if penalty == nil || penalty.CurrencyCode() == currencyCode {
+		return penalty, nil
+	}
+	if fx == nil {
+		return nil, fmt.Errorf("cannot convert penalty from %s to %s without an FX function", penalty.CurrencyCode(), currencyCode)
+	}
+
+	converted, err := fx(*penalty, currencyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &converted, nil
+}
+
+// PassengerManifest returns every passenger on the order with the services that apply to them,
+// resolved through each service's passenger IDs.
+func (o *Order) PassengerManifest() []PassengerManifestEntry {
+	manifest := make([]PassengerManifestEntry, len(o.Passengers))
+	for i, passenger := range o.Passengers {
+		manifest[i].Passenger = passenger
+		for _, service := range o.Services {
+			if slices.Contains(service.PassengerIDs, passenger.ID) {
+				manifest[i].Services = append(manifest[i].Services, service)
+			}
+		}
+	}
+	return manifest
+}
+
+// ServicesOfType returns the passenger's services of the given type, e.g. ServiceTypeSeat.
+func (e PassengerManifestEntry) ServicesOfType(t ServiceType) []Service {
+	services := make([]Service, 0)
+	for _, service := range e.Services {
+		if ServiceType(service.Type) == t {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// LoyaltyAccounts returns the loyalty programme accounts attached to the order, keyed by passenger ID.
+// Passengers without accounts are omitted.
+func (o *Order) LoyaltyAccounts() map[string][]LoyaltyProgrammeAccount {
+	accounts := make(map[string][]LoyaltyProgrammeAccount)
+	for _, passenger := range o.Passengers {
+		if len(passenger.LoyaltyProgrammeAccounts) > 0 {
+			accounts[passenger.ID] = passenger.LoyaltyProgrammeAccounts
+		}
+	}
+	return accounts
+}
+
+// TicketingDeadline returns the time by which the order must be paid for the airline to issue
+// tickets, or nil if there is none.
+//
+// Duffel does not expose a ticketing deadline for instant orders: tickets are issued on creation,
+// and an order that is paid but still has no documents should be reported to Duffel support. For
+// orders awaiting payment, the deadline is PaymentStatus.PaymentRequiredBy, after which the airline
+// cancels the booking.
+func (o *Order) TicketingDeadline() *time.Time {
+	if !o.PaymentStatus.AwaitingPayment {
+		return nil
+	}
+	return o.PaymentStatus.PaymentRequiredBy
+}
+
+// CanAddServices returns true if services can be added to the order after booking.
+// Duffel has no capability flag for it: services cannot be added to self-managed orders, booked with
+// the airline through your own credentials, nor to cancelled orders. The airline may still reject a
+// service, which ListOrderServices reflects by not offering it. AddServiceToOrder checks it before
+// adding a service; AddOrderService only takes an order ID, so it does not.
+func (o *Order) CanAddServices() bool {
+	return o.Content != OrderContentSelfManaged && o.CancelledAt == nil
+}
+
+// ConditionsByPassenger returns the conditions that apply to each passenger of the order, keyed by
+// passenger ID.
+//
+// Duffel does not return per-passenger conditions: an order has a single fare per slice, and its
+// conditions are the most restrictive across passengers. Every passenger therefore gets the order-level
+// Conditions; differences by passenger type are only visible in each segment passenger's FareBasisCode.
+func (o *Order) ConditionsByPassenger() map[string]Conditions {
+	conditions := make(map[string]Conditions, len(o.Passengers))
+	for _, passenger := range o.Passengers {
+		conditions[passenger.ID] = o.Conditions
+	}
+	return conditions
+}
+
+// Emissions returns the estimated CO2 emissions of the order in kilograms.
+// It fails when Duffel did not return emissions for the order or they cannot be parsed; keep the
+// offer's TotalEmissionsKg at booking time to report on such orders.
+func (o *Order) Emissions() (float64, error) {
+	if o.TotalEmissionsKg == nil || *o.TotalEmissionsKg == "" {
+		return 0, fmt.Errorf("order %s has no emissions data", o.ID)
+	}
+	kg, err := strconv.ParseFloat(*o.TotalEmissionsKg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse emissions of order %s: %w", o.ID, err)
+	}
+	return kg, nil
+}
+
+// HoldExpired returns true if the order is a hold order still awaiting payment
+// whose PaymentStatus.PaymentRequiredBy has passed; the airline cancels it.
+func (o *Order) HoldExpired() bool {
+	if o.Type != OrderTypeHold || !o.PaymentStatus.AwaitingPayment || o.PaymentStatus.PaymentRequiredBy == nil {
+		return false
+	}
+	return !time.Now().Before(*o.PaymentStatus.PaymentRequiredBy)
+}
+
+// Currencies returns the distinct currency codes appearing in the order amounts (total, base, tax,
+// services, changes and cancellation), in order of appearance. Amount helpers return zero values
+// when currencies differ, so use it to detect mixed-currency orders before doing arithmetic.
+func (o *Order) Currencies() []string {
+	currencies := make([]string, 0)
+	add := func(code string) {
+		if code != "" && !slices.Contains(currencies, code) {
+			currencies = append(currencies, code)
+		}
+	}
+
+	add(o.RawTotalCurrency)
+	if o.RawBaseCurrency != nil {
+		add(*o.RawBaseCurrency)
+	}
+	if o.RawTaxCurrency != nil {
+		add(*o.RawTaxCurrency)
+	}
+	for _, service := range o.Services {
+		add(service.RawTotalCurrency)
+	}
+	for _, change := range o.Changes {
+		add(change.RawChangeTotalCurrency)
+		add(change.RawNewTotalCurrency)
+		add(change.RawPenaltyCurrency)
+	}
+	if o.Cancellation != nil {
+		add(o.Cancellation.RawRefundCurrency)
+	}
+	return currencies
+}
+
+// RefundDestination returns where the refund of the order goes when it is cancelled, if known.
+//
+// Duffel's refund conditions only say whether a refund is allowed and its penalty, not its destination.
+// Once the order is cancelled, the destination is read from its cancellation. Before that, pass the
+// pending cancellation returned by CreateOrderCancellation, a quote that is not binding until
+// ConfirmOrderCancellation; cancellations of other orders are ignored.
+func (o *Order) RefundDestination(pending ...*OrderCancellation) (PaymentMethod, bool) {
+	if o.Cancellation != nil && o.Cancellation.RefundTo != "" {
+		return o.Cancellation.RefundTo, true
+	}
+	for _, cancellation := range pending {
+		if cancellation != nil && cancellation.OrderID == o.ID && cancellation.RefundTo != "" {
+			return cancellation.RefundTo, true
+		}
+	}
+	return "", false
+}
+
+// FareBasisCodes returns the fare basis code of each segment of the order, keyed by segment ID.
+// When passengers on a segment have different codes, the first non-empty one is kept.
+func (o *Order) FareBasisCodes() map[string]string {
+	codes := make(map[string]string)
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			for _, passenger := range segment.Passengers {
+				if passenger.FareBasisCode != "" {
+					codes[segment.ID] = passenger.FareBasisCode
+					break
+				}
+			}
+		}
+	}
+	return codes
+}
+
+// Merge returns a copy of the metadata with the updates applied: keys are added or overwritten,
+// and keys updated to nil are removed.
+func (m Metadata) Merge(updates map[string]any) Metadata {
+	merged := make(Metadata, len(m)+len(updates))
+	for key, value := range m {
+		merged[key] = value
+	}
+	for key, value := range updates {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// FilterOrdersByMetadata returns the orders whose metadata has the given key set to value.
+// Duffel cannot filter orders by metadata, so this is meant to be applied to ListOrders results.
+// Values are compared with reflect.DeepEqual; Duffel returns metadata values as strings.
+func FilterOrdersByMetadata(orders []*Order, key string, value any) []*Order {
+	filtered := make([]*Order, 0)
+	for _, order := range orders {
+		if order == nil {
+			continue
+		}
+		if v, ok := order.Metadata[key]; ok && reflect.DeepEqual(v, value) {
+			filtered = append(filtered, order)
+		}
+	}
+	return filtered
+}
+
+// RequiresIdentityDocuments returns true if the offer the order was booked from requires identity
+// documents and at least one passenger on the order has none.
+//
+// Orders don't carry the requirement themselves, so it is read from the offer. Duffel does not
+// support adding identity documents to an existing order; they must be provided in CreateOrderInput.
+func (o *Order) RequiresIdentityDocuments(offer *Offer) bool {
+	if offer == nil || !offer.PassengerIdentityDocumentsRequired {
+		return false
+	}
+	for _, passenger := range o.Passengers {
+		if len(passenger.IdentityDocuments) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeTotalAmount returns the amount paid for the change, negative when money was returned.
+func (c *PassengerInitiatedChanges) ChangeTotalAmount() currency.Amount {
+	amount, err := currency.NewAmount(c.RawChangeTotalAmount, c.RawChangeTotalCurrency)
+	if err != nil {
+		return currency.Amount{}
+	}
+	return amount
+}
+
+// NewTotalAmount returns the total amount of the order after the change.
+func (c *PassengerInitiatedChanges) NewTotalAmount() currency.Amount {
+	amount, err := currency.NewAmount(c.RawNewTotalAmount, c.RawNewTotalCurrency)
+	if err != nil {
+		return currency.Amount{}
+	}
+	return amount
+}
+
+// PenaltyTotalAmount returns the penalty imposed by the airline for the change.
+func (c *PassengerInitiatedChanges) PenaltyTotalAmount() currency.Amount {
+	amount, err := currency.NewAmount(c.RawPenaltyAmount, c.RawPenaltyCurrency)
+	if err != nil {
+		return currency.Amount{}
+	}
+	return amount
+}
+
+// RefundAmount returns the amount refunded to RefundTo by the change, or a zero amount when the change
+// was paid for. Duffel reports refunds as a negative ChangeTotalAmount.
+func (c *PassengerInitiatedChanges) RefundAmount() currency.Amount {
+	amount := c.ChangeTotalAmount()
+	if !amount.IsNegative() {
+		return currency.Amount{}
+	}
+	refund, err := amount.Mul("-1")
+	if err != nil {
+		return currency.Amount{}
+	}
+	return refund
+}
+
+// Conditions returns the conditions of the slices added by the change, keyed by slice ID.
+//
+// Duffel does not return conditions on a confirmed change itself. The flexibility of the new itinerary
+// is carried by the added slices, and by the order's Conditions once it reflects the change.
+func (c *PassengerInitiatedChanges) Conditions() map[string]SliceConditions {
+	conditions := make(map[string]SliceConditions, len(c.Slices.Add))
+	for _, slice := range c.Slices.Add {
+		conditions[slice.ID] = slice.Conditions
+	}
+	return conditions
+}
+
+// Policy returns whether the modification is not allowed, free, or allowed with a penalty.
+// A nil condition, or an allowed one without a penalty amount, is ChangeUnknown.
+func (c *ChangeCondition) Policy() ChangePolicy {
+	if c == nil {
+		return ChangeUnknown
+	}
+	if !c.Allowed {
+		return ChangeNotAllowed
+	}
+
+	penalty := c.PenaltyAmount()
+	switch {
+	case penalty == nil:
+		return ChangeUnknown
+	case penalty.IsZero():
+		return ChangeFree
+	default:
+		return ChangeWithPenalty
+	}
+}
+
+func (p ChangePolicy) String() string {
+	return string(p)
+}
+
 func (s *Service) TotalAmount() currency.Amount {
 	amount, err := currency.NewAmount(s.RawTotalAmount, s.RawTotalCurrency)
 	if err != nil {
@@ -434,7 +1066,66 @@ func (s *Service) TotalAmount() currency.Amount {
 func (o ListOrdersParams) Encode(q url.Values) error {
 	enc := schema.NewEncoder()
 	enc.SetAliasTag("url")
-	return enc.Encode(o, q)
+	err := enc.Encode(o, q)
+	if err != nil {
+		return err
+	}
+
+	o.DepartingAt.encode(q, "departing_at")
+	o.ArrivingAt.encode(q, "arriving_at")
+	o.CreatedAt.encode(q, "created_at")
+	return nil
+}
+
+// encode sets the bounds of the filter as name[before] and name[after] in RFC3339 format.
+func (f *TimeFilter) encode(q url.Values, name string) {
+	if f == nil {
+		return
+	}
+	if f.Before != nil {
+		q.Set(name+"[before]", f.Before.Format(time.RFC3339))
+	}
+	if f.After != nil {
+		q.Set(name+"[after]", f.After.Format(time.RFC3339))
+	}
+}
+
+var timeFilterFormats = []string{
+	DateFormat,
+	time.RFC3339,
+}
+
+// ParseTimeFilter builds a TimeFilter from date-only (2006-01-02) or RFC3339 strings.
+// An empty string leaves that bound unset.
+func ParseTimeFilter(after, before string) (*TimeFilter, error) {
+	afterTime, err := parseTimeFilterValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeTime, err := parseTimeFilterValue(before)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimeFilter{
+		After:  afterTime,
+		Before: beforeTime,
+	}, nil
+}
+
+func parseTimeFilterValue(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, format := range timeFilterFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to parse time filter value: '%s'", value)
 }
 
 func (l ListAirlineInitiatedChangesParams) Encode(q url.Values) error {
@@ -444,3 +1135,11 @@ func (l ListAirlineInitiatedChangesParams) Encode(q url.Values) error {
 
 	return nil
 }
+
+var (
+	_ OrderResumer             = (*API)(nil)
+	_ CancelForAnyReasonClient = (*API)(nil)
+	_ DuplicateOrderFinder     = (*API)(nil)
+	_ ExpiredHoldOrderLister   = (*API)(nil)
+	_ OrderMetadataUpdater     = (*API)(nil)
+)
@@ -6,9 +6,12 @@ package duffel
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/bojanz/currency"
 	"github.com/segmentio/encoding/json"
 	"gopkg.in/h2non/gock.v1"
 
@@ -143,6 +146,41 @@ func TestCreateOrderAwaitingCreation(t *testing.T) {
 	a.Equal("The booking has been confirmed. It will appear in the system soon.", *order.Message)
 }
 
+func TestCreateOrderForOffer(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/orders").
+		BodyString(`"selected_offers":\["off_123"\]`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-create-order-awaiting.json")
+
+	ctx := context.TODO()
+	client := New("duffel_test_123").(*API)
+	input := CreateOrderInput{Type: OrderTypeInstant, Passengers: []OrderPassenger{}}
+
+	_, err := client.CreateOrderForOffer(ctx, &Offer{ID: "off_partial", Partial: true}, input)
+	a.ErrorIs(err, ErrPartialOffer)
+	_, err = client.CreateOrderForOffer(ctx, nil, input)
+	a.Error(err)
+	_, err = client.CreateOrderForOffer(
+		ctx, &Offer{ID: "off_123"}, CreateOrderInput{Type: OrderTypeInstant, SelectedOffers: []string{"off_456"}},
+	)
+	a.ErrorContains(err, "instead of offer off_123")
+	a.True(gock.IsPending())
+
+	input.SelectedOffers = []string{"off_123"}
+	order, err := client.CreateOrderForOffer(ctx, &Offer{ID: "off_123"}, input)
+	a.NoError(err)
+	a.True(order.IsAwaitingCreation)
+	a.True(gock.IsDone())
+}
+
 func TestCreateOrderPendingConfirmation(t *testing.T) {
 	defer gock.Off()
 	a := assert.New(t)
@@ -310,3 +348,593 @@ func TestUpdateOrder(t *testing.T) {
 	a.Equal(Metadata{"seat_preference": "window"}, order.Metadata)
 	a.Equal("ord_00009hthhsUZ8W4LxQgkjo", order.ID)
 }
+
+func TestParseTimeFilter(t *testing.T) {
+	a := assert.New(t)
+
+	filter, err := ParseTimeFilter("2024-06-01", "2024-06-30T18:00:00+02:00")
+	a.NoError(err)
+	a.Equal(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), *filter.After)
+	a.True(time.Date(2024, time.June, 30, 16, 0, 0, 0, time.UTC).Equal(*filter.Before))
+
+	filter, err = ParseTimeFilter("", "2024-06-30")
+	a.NoError(err)
+	a.Nil(filter.After)
+	a.NotNil(filter.Before)
+
+	_, err = ParseTimeFilter("01/06/2024", "")
+	a.EqualError(err, "failed to parse time filter value: '01/06/2024'")
+}
+
+func TestConditionDeadlinePassed(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithClock(clock)).(*API)
+	now := clock.Now()
+	condition := &ChangeCondition{Allowed: true}
+	a.False(client.ConditionDeadlinePassed(condition, now.Add(time.Hour)))
+	a.True(client.ConditionDeadlinePassed(condition, now))
+	a.True(client.ConditionDeadlinePassed(condition, now.Add(-time.Hour)))
+	a.True(client.ConditionDeadlinePassed(&ChangeCondition{Allowed: false}, now.Add(time.Hour)))
+	a.True(client.ConditionDeadlinePassed(nil, now.Add(time.Hour)))
+}
+
+func loadOrderFixture(t *testing.T, path string) *Order {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := new(Payload[*Order])
+	if err := json.Unmarshal(b, payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload.Data
+}
+
+func TestOrderPassengerManifest(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	manifest := order.PassengerManifest()
+	a.Len(manifest, 1)
+	a.Equal("Amelia", manifest[0].Passenger.GivenName)
+	a.Len(manifest[0].Services, 1)
+
+	seats := manifest[0].ServicesOfType(ServiceTypeSeat)
+	a.Len(seats, 1)
+	a.Equal("ser_00009UhD4ongolulWd9123", seats[0].ID)
+	a.Empty(manifest[0].ServicesOfType(ServiceTypeBaggage))
+}
+
+func TestOrderRequiresIdentityDocuments(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	a.False(order.RequiresIdentityDocuments(nil))
+	a.False(order.RequiresIdentityDocuments(&Offer{PassengerIdentityDocumentsRequired: false}))
+
+	order.Passengers[0].IdentityDocuments = nil
+	a.True(order.RequiresIdentityDocuments(&Offer{PassengerIdentityDocumentsRequired: true}))
+
+	order.Passengers[0].IdentityDocuments = []IdentityDocument{{Type: PassengerIdentityDocumentTypePassport}}
+	a.False(order.RequiresIdentityDocuments(&Offer{PassengerIdentityDocumentsRequired: true}))
+}
+
+func TestOrderFareBasisCodes(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	a.Equal(map[string]string{"seg_00009htYpSCXrwaB9Dn456": "OXZ0RO"}, order.FareBasisCodes())
+	a.Equal("O", order.Slices[0].Segments[0].Passengers[0].GuessBookingClass())
+	a.Equal("", SegmentPassenger{}.GuessBookingClass())
+}
+
+func TestOrderConditionsByPassenger(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	conditions := order.ConditionsByPassenger()
+	a.Len(conditions, 1)
+	a.Equal(order.Conditions, conditions["pas_00009hj8USM7Ncg31cBCLL"])
+}
+
+func TestChangeConditionPenaltyAmountIn(t *testing.T) {
+	a := assert.New(t)
+	fx := func(amount currency.Amount, currencyCode string) (currency.Amount, error) {
+		converted, err := amount.Convert(currencyCode, "1.2")
+		return converted.Round(), err
+	}
+
+	penalty, err := (&ChangeCondition{Allowed: true}).PenaltyAmountIn("EUR", fx)
+	a.NoError(err)
+	a.Nil(penalty)
+
+	empty := ""
+	penalty, err = (&ChangeCondition{Allowed: true, RawPenaltyAmount: &empty, RawPenaltyCurrency: &empty}).
+		PenaltyAmountIn("EUR", fx)
+	a.NoError(err)
+	a.Nil(penalty)
+
+	var missing *ChangeCondition
+	a.Nil(missing.PenaltyAmount())
+
+	amount, code := "50.00", "GBP"
+	condition := &ChangeCondition{Allowed: true, RawPenaltyAmount: &amount, RawPenaltyCurrency: &code}
+
+	penalty, err = condition.PenaltyAmountIn("GBP", nil)
+	a.NoError(err)
+	a.Equal("50.00 GBP", penalty.String())
+
+	penalty, err = condition.PenaltyAmountIn("EUR", fx)
+	a.NoError(err)
+	a.Equal("60.00 EUR", penalty.String())
+
+	_, err = condition.PenaltyAmountIn("EUR", nil)
+	a.Error(err)
+}
+
+func TestFilterOrdersByMetadata(t *testing.T) {
+	a := assert.New(t)
+
+	web := &Order{ID: "ord_1", Metadata: Metadata{"channel": "web"}}
+	mobile := &Order{ID: "ord_2", Metadata: Metadata{"channel": "mobile"}}
+	untagged := &Order{ID: "ord_3"}
+
+	orders := []*Order{web, mobile, untagged, nil}
+	a.Equal([]*Order{web}, FilterOrdersByMetadata(orders, "channel", "web"))
+	a.Empty(FilterOrdersByMetadata(orders, "channel", "agency"))
+	a.Empty(FilterOrdersByMetadata(orders, "source", "web"))
+}
+
+func TestListOrdersAfter(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	cursor := "g2wAAAACbQAAABBBZXJvbWlzdC1LaGFya2l2bQAAAB="
+	gock.New("https://api.duffel.com").
+		Get("/air/orders").
+		MatchParam("after", cursor).
+		MatchParam("booking_reference", "RZPNX8").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-list-orders-page2.json")
+
+	client := New("duffel_test_123").(OrderResumer)
+	iter := client.ListOrdersAfter(context.TODO(), cursor, ListOrdersParams{BookingReference: "RZPNX8"})
+
+	a.Equal(cursor, iter.After())
+	a.True(iter.Next())
+	a.NoError(iter.Err())
+	a.Equal("ord_00009hthhsUZ8W4LxQgkjo", iter.Current().ID)
+	a.Equal("", iter.After())
+	a.False(iter.Next())
+	a.True(gock.IsDone())
+}
+
+func TestIterAfterResumesFromNextPage(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-list-orders.json")
+
+	client := New("duffel_test_123")
+	iter := client.ListOrders(context.TODO())
+
+	a.Equal("", iter.After())
+	a.True(iter.Next())
+	a.Equal("g2wAAAACbQAAABBBZXJvbWlzdC1LaGFya2l2bQAAAB=", iter.After())
+}
+
+func TestOrderCalendarEvents(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	events, err := order.CalendarEvents()
+	a.NoError(err)
+	a.Len(events, 1)
+
+	london, err := time.LoadLocation("Europe/London")
+	a.NoError(err)
+	newYork, err := time.LoadLocation("America/New_York")
+	a.NoError(err)
+
+	a.Equal("seg_00009htYpSCXrwaB9Dn456", events[0].UID)
+	a.Equal("BA1234 LHR→JFK", events[0].Summary)
+	a.Equal("Heathrow (LHR)", events[0].Location)
+	a.True(time.Date(2020, 6, 13, 16, 38, 2, 0, london).Equal(events[0].Start))
+	a.True(time.Date(2020, 6, 13, 16, 38, 2, 0, newYork).Equal(events[0].End))
+
+	order.Slices[0].Segments[0].Origin.TimeZone = "Nowhere/Unknown"
+	_, err = order.CalendarEvents()
+	a.Error(err)
+}
+
+func TestOrderLoyaltyAccounts(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/201-create-order.json")
+
+	a.Equal(
+		map[string][]LoyaltyProgrammeAccount{
+			"pas_00009hj8USM7Ncg31cBCLL": {{AirlineIATACode: "BA", AccountNumber: "12901014"}},
+		}, order.LoyaltyAccounts(),
+	)
+	a.Empty((&Order{Passengers: []OrderPassenger{{ID: "pas_123"}}}).LoyaltyAccounts())
+}
+
+func TestAddCancelForAnyReason(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	services := `{"data": [
+		{"id": "ase_seat", "type": "seat", "total_amount": "10.00", "total_currency": "GBP"},
+		{"id": "ase_cfar", "type": "cancel_for_any_reason", "total_amount": "20.00", "total_currency": "GBP",
+		 "metadata": {"merchant_copy": "Refund up to 75%", "refund_amount": "100.00", "terms_and_conditions_url": "https://example.com/terms"}}
+	]}`
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_00009hthhsUZ8W4LxQgkjo/available_services").
+		Times(3).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(services)
+	gock.New("https://api.duffel.com").
+		Post("/air/orders/ord_00009hthhsUZ8W4LxQgkjo/services").
+		BodyString(`"id":"ase_cfar","quantity":1`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/201-create-order.json")
+
+	ctx := context.TODO()
+	client := New("duffel_test_123").(CancelForAnyReasonClient)
+	payment := PaymentCreateInput{Amount: "20.00", Currency: "GBP", Type: PaymentMethodBalance}
+
+	order, err := client.AddCancelForAnyReason(ctx, "ord_00009hthhsUZ8W4LxQgkjo", "ase_cfar", payment)
+	a.NoError(err)
+	a.Equal("ord_00009hthhsUZ8W4LxQgkjo", order.ID)
+
+	_, err = client.AddCancelForAnyReason(ctx, "ord_00009hthhsUZ8W4LxQgkjo", "ase_seat", payment)
+	a.EqualError(err, "service ase_seat is not a cancel for any reason service")
+
+	_, err = client.AddCancelForAnyReason(ctx, "ord_00009hthhsUZ8W4LxQgkjo", "ase_unknown", payment)
+	a.EqualError(err, "service ase_unknown is not available on order ord_00009hthhsUZ8W4LxQgkjo")
+	a.True(gock.IsDone())
+}
+
+func TestAddServiceToOrder(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/orders/ord_00009hthhsUZ8W4LxQgkjo/services").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/201-create-order.json")
+
+	ctx := context.TODO()
+	client := New("duffel_test_123").(*API)
+	input := AddOrderServiceInput{AddServices: []ServiceCreateInput{{ID: "ase_bag", Quantity: 1}}}
+
+	_, err := client.AddServiceToOrder(ctx, &Order{ID: "ord_self", Content: OrderContentSelfManaged}, input)
+	a.ErrorIs(err, ErrServicesNotAllowed)
+	_, err = client.AddServiceToOrder(ctx, loadOrderFixture(t, "fixtures/200-get-order.json"), input)
+	a.ErrorIs(err, ErrServicesNotAllowed)
+	_, err = client.AddServiceToOrder(ctx, nil, input)
+	a.Error(err)
+	a.True(gock.IsPending())
+
+	order, err := client.AddServiceToOrder(
+		ctx, &Order{ID: "ord_00009hthhsUZ8W4LxQgkjo", Content: OrderContentManaged}, input,
+	)
+	a.NoError(err)
+	a.Equal("ord_00009hthhsUZ8W4LxQgkjo", order.ID)
+	a.True(gock.IsDone())
+}
+
+func TestOrderCanAddServices(t *testing.T) {
+	a := assert.New(t)
+
+	a.True((&Order{Content: OrderContentManaged}).CanAddServices())
+	a.False((&Order{Content: OrderContentSelfManaged}).CanAddServices())
+	a.False(loadOrderFixture(t, "fixtures/200-get-order.json").CanAddServices())
+}
+
+func TestOrderTicketingDeadline(t *testing.T) {
+	a := assert.New(t)
+
+	order := loadOrderFixture(t, "fixtures/201-create-order.json")
+	a.True(order.PaymentStatus.AwaitingPayment)
+	a.Equal(order.PaymentStatus.PaymentRequiredBy, order.TicketingDeadline())
+
+	order.PaymentStatus.AwaitingPayment = false
+	a.Nil(order.TicketingDeadline())
+}
+
+func TestOrderCurrencies(t *testing.T) {
+	a := assert.New(t)
+
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+	a.Equal([]string{"GBP"}, order.Currencies())
+
+	order.Services = append(order.Services, Service{RawTotalCurrency: "EUR"})
+	order.Cancellation = &OrderCancellation{RawRefundCurrency: "USD"}
+	a.Equal([]string{"GBP", "EUR", "USD"}, order.Currencies())
+}
+
+func TestCreateOrderInputComputeRequiredPayment(t *testing.T) {
+	a := assert.New(t)
+
+	b, err := os.ReadFile("fixtures/200-offers-off_00009htYpSCXrwaB9DnUm0.json")
+	a.NoError(err)
+	var payload Payload[*Offer]
+	a.NoError(json.Unmarshal(b, &payload))
+	offer := payload.Data
+
+	input := CreateOrderInput{SelectedOffers: []string{offer.ID}}
+	amount, err := input.ComputeRequiredPayment(offer)
+	a.NoError(err)
+	a.Equal("45.00 GBP", amount.String())
+
+	input.Services = []ServiceCreateInput{{ID: "ase_00009UhD4ongolulWd9123", Quantity: 2}}
+	amount, err = input.ComputeRequiredPayment(offer)
+	a.NoError(err)
+	a.Equal("75.00 GBP", amount.String())
+
+	input.Services = []ServiceCreateInput{{ID: "ase_unknown", Quantity: 1}}
+	_, err = input.ComputeRequiredPayment(offer)
+	a.Error(err)
+
+	_, err = (&CreateOrderInput{SelectedOffers: []string{"off_other"}}).ComputeRequiredPayment(offer)
+	a.Error(err)
+}
+
+func TestFindExistingOrders(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders").
+		MatchParam("passenger_name", "Amelia Earhart").
+		MatchParam("departing_at[after]", "2020-06-13T00:00:00Z").
+		MatchParam("departing_at[before]", "2020-06-14T00:00:00Z").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-list-orders-page2.json")
+
+	departing, err := ParseTimeFilter("2020-06-13", "2020-06-14")
+	a.NoError(err)
+
+	client := New("duffel_test_123").(DuplicateOrderFinder)
+	orders, err := client.FindExistingOrders(context.TODO(), []string{"Amelia Earhart"}, departing)
+	a.NoError(err)
+	a.Len(orders, 1)
+	a.True(gock.IsDone())
+
+	_, err = client.FindExistingOrders(context.TODO(), nil, departing)
+	a.Error(err)
+}
+
+func TestOrderRefundDestination(t *testing.T) {
+	a := assert.New(t)
+
+	_, ok := (&Order{}).RefundDestination()
+	a.False(ok)
+
+	order := &Order{Cancellation: &OrderCancellation{RefundTo: PaymentMethodAirlineCredits}}
+	destination, ok := order.RefundDestination()
+	a.True(ok)
+	a.Equal(PaymentMethodAirlineCredits, destination)
+}
+
+func TestOrderRefundDestinationBeforeCancelling(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/order_cancellations").
+		Reply(201).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/201-create-order-cancellation.json")
+
+	client := New("duffel_test_123")
+	pending, err := client.CreateOrderCancellation(context.TODO(), "ord_00009hthhsUZ8W4LxQgkjo")
+	a.NoError(err)
+
+	order := &Order{ID: "ord_00009hthhsUZ8W4LxQgkjo"}
+	_, ok := order.RefundDestination()
+	a.False(ok)
+
+	destination, ok := order.RefundDestination(pending)
+	a.True(ok)
+	a.Equal(PaymentMethodARCBSPCash, destination)
+
+	_, ok = (&Order{ID: "ord_other"}).RefundDestination(pending)
+	a.False(ok)
+}
+
+func TestChangeConditionPolicy(t *testing.T) {
+	a := assert.New(t)
+	amount := func(v string) *string { return &v }
+	gbp := amount("GBP")
+
+	var missing *ChangeCondition
+	a.Equal(ChangeUnknown, missing.Policy())
+	a.Equal(ChangeNotAllowed, (&ChangeCondition{Allowed: false}).Policy())
+	a.Equal(ChangeUnknown, (&ChangeCondition{Allowed: true}).Policy())
+	a.Equal(ChangeFree, (&ChangeCondition{Allowed: true, RawPenaltyAmount: amount("0.00"), RawPenaltyCurrency: gbp}).Policy())
+	a.Equal(
+		ChangeWithPenalty,
+		(&ChangeCondition{Allowed: true, RawPenaltyAmount: amount("50.00"), RawPenaltyCurrency: gbp}).Policy(),
+	)
+	a.Equal("with_penalty", ChangeWithPenalty.String())
+}
+
+func TestOrderHoldExpired(t *testing.T) {
+	a := assert.New(t)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	a.True((&Order{Type: OrderTypeHold, PaymentStatus: PaymentStatus{AwaitingPayment: true, PaymentRequiredBy: &past}}).HoldExpired())
+	a.False((&Order{Type: OrderTypeHold, PaymentStatus: PaymentStatus{AwaitingPayment: true, PaymentRequiredBy: &future}}).HoldExpired())
+	a.False((&Order{Type: OrderTypeHold, PaymentStatus: PaymentStatus{AwaitingPayment: false, PaymentRequiredBy: &past}}).HoldExpired())
+	a.False((&Order{Type: OrderTypeHold, PaymentStatus: PaymentStatus{AwaitingPayment: true}}).HoldExpired())
+	a.False((&Order{Type: OrderTypeInstant, PaymentStatus: PaymentStatus{AwaitingPayment: true, PaymentRequiredBy: &past}}).HoldExpired())
+}
+
+func TestExpiredHoldOrders(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders").
+		MatchParam("awaiting_payment", "true").
+		MatchParam("sort", "payment_required_by").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(fmt.Sprintf(
+			`{"meta":{"limit":50,"after":null},"data":[`+
+				`{"id":"ord_expired","type":"hold","payment_status":{"awaiting_payment":true,"payment_required_by":%q}},`+
+				`{"id":"ord_pending","type":"hold","payment_status":{"awaiting_payment":true,"payment_required_by":%q}}]}`,
+			time.Now().Add(-time.Hour).Format(time.RFC3339), time.Now().Add(time.Hour).Format(time.RFC3339),
+		))
+
+	client := New("duffel_test_123").(ExpiredHoldOrderLister)
+	orders, err := client.ExpiredHoldOrders(context.TODO())
+	a.NoError(err)
+	if a.Len(orders, 1) {
+		a.Equal("ord_expired", orders[0].ID)
+	}
+	a.True(gock.IsDone())
+}
+
+func TestPassengerInitiatedChangesAmounts(t *testing.T) {
+	a := assert.New(t)
+
+	var change PassengerInitiatedChanges
+	err := json.Unmarshal(
+		[]byte(`{
+			"id": "oce_123",
+			"change_total_amount": "-30.00",
+			"change_total_currency": "GBP",
+			"new_total_amount": "90.00",
+			"new_total_currency": "GBP",
+			"penalty_total_amount": "10.00",
+			"penalty_total_currency": "GBP",
+			"slices": {
+				"add": [{"id": "sli_new", "conditions": {"change_before_departure": {"allowed": false}}}],
+				"remove": [{"id": "sli_old"}]
+			}
+		}`), &change,
+	)
+	a.NoError(err)
+
+	a.Equal("-30.00 GBP", change.ChangeTotalAmount().String())
+	a.Equal("30.00 GBP", change.RefundAmount().String())
+	a.Equal("90.00 GBP", change.NewTotalAmount().String())
+	a.Equal("10.00 GBP", change.PenaltyTotalAmount().String())
+	a.Equal(ChangeNotAllowed, change.Conditions()["sli_new"].ChangeBeforeDeparture.Policy())
+
+	change.RawChangeTotalAmount = "15.00"
+	a.True(change.RefundAmount().IsZero())
+}
+
+func TestOrderEmissions(t *testing.T) {
+	a := assert.New(t)
+
+	var order Order
+	a.NoError(json.Unmarshal([]byte(`{"id":"ord_123","total_emissions_kg":"460.5"}`), &order))
+	kg, err := order.Emissions()
+	a.NoError(err)
+	a.Equal(460.5, kg)
+
+	_, err = (&Order{ID: "ord_456"}).Emissions()
+	a.Error(err)
+}
+
+func TestMetadataMerge(t *testing.T) {
+	a := assert.New(t)
+
+	existing := Metadata{"ref": "old", "keep": "yes", "drop": "me"}
+	merged := existing.Merge(map[string]any{"ref": "new", "drop": nil, "add": "1"})
+	a.Equal(Metadata{"ref": "new", "keep": "yes", "add": "1"}, merged)
+	a.Equal("old", existing["ref"])
+}
+
+func TestUpdateOrdersMetadata(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_1").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"data":{"id":"ord_1","metadata":{"ref":"old","keep":"yes"}}}`)
+	gock.New("https://api.duffel.com").
+		Patch("/air/orders/ord_1").
+		MatchType("json").
+		JSON(Payload[OrderUpdateParams]{Data: OrderUpdateParams{Metadata: map[string]any{"ref": "new", "keep": "yes"}}}).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"data":{"id":"ord_1","metadata":{"ref":"new","keep":"yes"}}}`)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_2").
+		Reply(404).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"errors":[{"type":"invalid_request_error","code":"not_found","message":"Not found"}],"meta":{"status":404}}`)
+
+	client := New("duffel_test_123").(OrderMetadataUpdater)
+	orders, errs := client.UpdateOrdersMetadata(
+		context.TODO(), map[string]map[string]any{
+			"ord_1": {"ref": "new"},
+			"ord_2": {"ref": "new"},
+		},
+	)
+	if a.Len(errs, 1) {
+		a.ErrorContains(errs[0], "order ord_2")
+	}
+	if a.Contains(orders, "ord_1") {
+		a.Equal("new", orders["ord_1"].Metadata["ref"])
+	}
+	a.NotContains(orders, "ord_2")
+	a.True(gock.IsDone())
+}
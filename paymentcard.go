@@ -2,8 +2,13 @@ package duffel
 
 import (
 	"context"
+	"fmt"
+	"slices"
 )
 
+// ErrUnsupportedCardBrand is returned by PaymentCard.CheckBrand when the card brand is not accepted.
+var ErrUnsupportedCardBrand = fmt.Errorf("duffel: unsupported payment card brand")
+
 type (
 	CreatePaymentCardRecordRequest struct {
 		AddressCity        string `json:"address_city"`
@@ -48,6 +53,14 @@ type (
 		) (*PaymentCard, error)
 		DeleteSavedPaymentCardRecord(ctx context.Context, id string) error
 	}
+
+	// IdempotentPaymentCardClient is implemented by the client returned by New. It is kept out of Duffel so that
+	// other implementations, such as mocks, need not provide it: use client.(duffel.IdempotentPaymentCardClient).
+	IdempotentPaymentCardClient interface {
+		GetOrCreatePaymentCard(
+			ctx context.Context, payload *CreatePaymentCardRecordRequest, idempotencyKey string,
+		) (*PaymentCard, error)
+	}
 )
 
 const (
@@ -75,10 +88,50 @@ func (a *API) CreateTemporaryPaymentCardRecordFromSavedPaymentCardRecord(
 		Single(ctx)
 }
 
+// IsVisa returns true if Duffel detected the card as a Visa card.
+func (c *PaymentCard) IsVisa() bool {
+	return c.Brand == CardBrandVisa
+}
+
+// IsMastercard returns true if Duffel detected the card as a Mastercard card.
+func (c *PaymentCard) IsMastercard() bool {
+	return c.Brand == CardBrandMastercard
+}
+
+// IsAmex returns true if Duffel detected the card as an American Express card.
+func (c *PaymentCard) IsAmex() bool {
+	return c.Brand == CardBrandAmericanExpress
+}
+
+// CheckBrand returns ErrUnsupportedCardBrand if the brand Duffel detected on the vaulted card is not one
+// of the accepted brands. Use it after vaulting a card and before paying with it, as some airlines do not
+// take every brand.
+func (c *PaymentCard) CheckBrand(accepted ...PaymentCardBrand) error {
+	if !slices.Contains(accepted, c.Brand) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedCardBrand, c.Brand)
+	}
+	return nil
+}
+
+// GetOrCreatePaymentCard creates a payment card record with the given idempotency key, so that retrying
+// it with the same key, e.g. after a network error during checkout, returns the card vaulted by the
+// first attempt instead of a duplicate. Derive the key from something stable for the checkout.
+func (a *API) GetOrCreatePaymentCard(
+	ctx context.Context, payload *CreatePaymentCardRecordRequest, idempotencyKey string,
+) (*PaymentCard, error) {
+	if idempotencyKey == "" {
+		return nil, fmt.Errorf("idempotency key is required")
+	}
+	return a.CreatePaymentCardRecord(WithCallOptions(ctx, WithIdempotencyKey(idempotencyKey)), payload)
+}
+
 func (a *API) DeleteSavedPaymentCardRecord(ctx context.Context, id string) error {
 	return newRequestWithAPI[EmptyPayload, EmptyPayload](a).
 		Deletef("/vault/cards/%s", id).
 		Empty(ctx)
 }
 
-var _ AircraftClient = (*API)(nil)
+var (
+	_ PaymentCardClient           = (*API)(nil)
+	_ IdempotentPaymentCardClient = (*API)(nil)
+)
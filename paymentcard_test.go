@@ -0,0 +1,56 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetOrCreatePaymentCard(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/vault/cards").
+		MatchHeader(IdempotencyKeyHeader, "^checkout_123$").
+		Times(2).
+		Reply(201).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		BodyString(`{"data":{"id":"tcd_123","last_4_digits":"4242","brand":"visa","multi_use":true}}`)
+
+	client := New("duffel_test_123").(IdempotentPaymentCardClient)
+	payload := &CreatePaymentCardRecordRequest{Number: "4242424242424242", MultiUse: true}
+	first, err := client.GetOrCreatePaymentCard(context.TODO(), payload, "checkout_123")
+	a.NoError(err)
+	second, err := client.GetOrCreatePaymentCard(context.TODO(), payload, "checkout_123")
+	a.NoError(err)
+	a.Equal(first.ID, second.ID)
+	a.True(gock.IsDone())
+
+	_, err = client.GetOrCreatePaymentCard(context.TODO(), payload, "")
+	a.Error(err)
+}
+
+func TestPaymentCardBrand(t *testing.T) {
+	a := assert.New(t)
+
+	card := &PaymentCard{Brand: CardBrandAmericanExpress}
+	a.True(card.IsAmex())
+	a.False(card.IsVisa())
+	a.False(card.IsMastercard())
+	a.NoError(card.CheckBrand(CardBrandVisa, CardBrandAmericanExpress))
+
+	card.Brand = CardBrandDinersClub
+	a.ErrorIs(card.CheckBrand(CardBrandVisa, CardBrandAmericanExpress), ErrUnsupportedCardBrand)
+	a.ErrorIs((&PaymentCard{}).CheckBrand(CardBrandVisa), ErrUnsupportedCardBrand)
+}
@@ -6,6 +6,7 @@ package duffel
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -50,3 +51,64 @@ func TestCreatePayment(t *testing.T) {
 	a.NoError(err)
 	a.Equal(expected, payment)
 }
+
+func TestCreatePaymentGeneratesIdempotencyKey(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/payments").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+	gock.New("https://api.duffel.com").
+		Post("/air/payments").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-create-payment.json")
+
+	var keys []string
+	client := New(
+		"duffel_test_123",
+		WithIdempotencyKeys(),
+		WithRetries(2, time.Second),
+		WithClock(newFakeClock()),
+		WithHTTPClient(&http.Client{
+			Transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				keys = append(keys, req.Header.Get(IdempotencyKeyHeader))
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		}),
+	)
+	_, err := client.CreatePayment(context.TODO(), CreatePaymentRequest{OrderID: "ord_00003x8pVDGcS8y2AWCoWv"})
+	a.NoError(err)
+
+	// The retry is sent with the key of the first attempt.
+	if a.Len(keys, 2) {
+		a.Regexp("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", keys[0])
+		a.Equal(keys[0], keys[1])
+	}
+}
+
+func TestCreatePaymentIdempotencyKeyOverride(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Post("/air/payments").
+		MatchHeader(IdempotencyKeyHeader, "^pay_order_123$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-create-payment.json")
+
+	client := New("duffel_test_123", WithIdempotencyKeys())
+	ctx := WithCallOptions(context.TODO(), WithIdempotencyKey("pay_order_123"))
+	_, err := client.CreatePayment(ctx, CreatePaymentRequest{OrderID: "ord_00003x8pVDGcS8y2AWCoWv"})
+	a.NoError(err)
+	a.True(gock.IsDone())
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"github.com/bojanz/currency"
+)
+
+type (
+	// PNR is a vendor-neutral view of an order, shaped like a GDS passenger name record,
+	// for mid-office systems that expect one.
+	PNR struct {
+		// RecordLocator is the airline booking reference.
+		RecordLocator string
+		// ValidatingCarrier is the IATA code of the airline owning the order.
+		ValidatingCarrier string
+		Passengers        []PNRPassenger
+		// Segments are listed in itinerary order.
+		Segments []PNRSegment
+		Fare     PNRFare
+		Tickets  []PNRTicket
+	}
+
+	PNRPassenger struct {
+		ID         string
+		Type       PassengerType
+		Title      PassengerTitle
+		GivenName  string
+		FamilyName string
+		BornOn     Date
+		Email      string
+		Phone      string
+	}
+
+	PNRSegment struct {
+		ID                    string
+		MarketingCarrier      string
+		MarketingFlightNumber string
+		OperatingCarrier      string
+		OperatingFlightNumber string
+		Origin                string
+		Destination           string
+		// DepartingAt and ArrivingAt are local times at the airports, as returned by Duffel.
+		DepartingAt string
+		ArrivingAt  string
+		CabinClass  CabinClass
+		FareBasis   string
+		Aircraft    string
+	}
+
+	PNRFare struct {
+		Total currency.Amount
+		// Base and Tax are nil when Duffel does not break the total down.
+		Base *currency.Amount
+		Tax  *currency.Amount
+	}
+
+	PNRTicket struct {
+		Number       string
+		Type         IssuedDocumentType
+		PassengerIDs []string
+	}
+)
+
+// ToPNR maps the order to a PNR. It only reads the order and makes no request.
+// Segment cabin and fare basis are those of the first passenger on the segment. Duffel does not expose
+// the booking class, so it is not part of the PNR.
+func (o *Order) ToPNR() PNR {
+	pnr := PNR{
+		RecordLocator:     o.BookingReference,
+		ValidatingCarrier: o.Owner.IATACode,
+		Passengers:        make([]PNRPassenger, 0, len(o.Passengers)),
+		Segments:          make([]PNRSegment, 0),
+		Fare: PNRFare{
+			Total: o.TotalAmount(),
+			Base:  o.BaseAmount(),
+			Tax:   o.TaxAmount(),
+		},
+		Tickets: make([]PNRTicket, 0, len(o.Documents)),
+	}
+
+	for _, passenger := range o.Passengers {
+		pnr.Passengers = append(
+			pnr.Passengers, PNRPassenger{
+				ID:         passenger.ID,
+				Type:       passenger.Type,
+				Title:      passenger.Title,
+				GivenName:  passenger.GivenName,
+				FamilyName: passenger.FamilyName,
+				BornOn:     passenger.BornOn,
+				Email:      passenger.Email,
+				Phone:      passenger.PhoneNumber,
+			},
+		)
+	}
+
+	for _, slice := range o.Slices {
+		for _, segment := range slice.Segments {
+			s := PNRSegment{
+				ID:                    segment.ID,
+				MarketingCarrier:      segment.MarketingCarrier.IATACode,
+				MarketingFlightNumber: segment.MarketingCarrierFlightNumber,
+				OperatingCarrier:      segment.OperatingCarrier.IATACode,
+				OperatingFlightNumber: segment.OperatingCarrierFlightNumber,
+				Origin:                segment.Origin.IATACode,
+				Destination:           segment.Destination.IATACode,
+				DepartingAt:           segment.RawDepartingAt,
+				ArrivingAt:            segment.RawArrivingAt,
+				Aircraft:              segment.Aircraft.IATACode,
+			}
+			if len(segment.Passengers) > 0 {
+				s.CabinClass = segment.Passengers[0].CabinClass
+				s.FareBasis = segment.Passengers[0].FareBasisCode
+			}
+			pnr.Segments = append(pnr.Segments, s)
+		}
+	}
+
+	for _, document := range o.Documents {
+		pnr.Tickets = append(
+			pnr.Tickets, PNRTicket{
+				Number:       document.UniqueIdentifier,
+				Type:         document.Type,
+				PassengerIDs: document.PassengerIDs,
+			},
+		)
+	}
+
+	return pnr
+}
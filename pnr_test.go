@@ -0,0 +1,49 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderToPNR(t *testing.T) {
+	a := assert.New(t)
+	order := loadOrderFixture(t, "fixtures/200-get-order.json")
+
+	pnr := order.ToPNR()
+	a.Equal("RZPNX8", pnr.RecordLocator)
+	a.Equal("BA", pnr.ValidatingCarrier)
+
+	a.Len(pnr.Passengers, 1)
+	a.Equal("pas_00009hj8USM7Ncg31cBCLL", pnr.Passengers[0].ID)
+	a.Equal("Amelia", pnr.Passengers[0].GivenName)
+	a.Equal(PassengerTypeAdult, pnr.Passengers[0].Type)
+
+	a.Equal(
+		[]PNRSegment{
+			{
+				ID:                    "seg_00009htYpSCXrwaB9Dn456",
+				MarketingCarrier:      "BA",
+				MarketingFlightNumber: "1234",
+				OperatingCarrier:      "BA",
+				OperatingFlightNumber: "4321",
+				Origin:                "LHR",
+				Destination:           "JFK",
+				DepartingAt:           "2020-06-13T16:38:02",
+				ArrivingAt:            "2020-06-13T16:38:02",
+				CabinClass:            CabinClassEconomy,
+				FareBasis:             "OXZ0RO",
+				Aircraft:              "380",
+			},
+		}, pnr.Segments,
+	)
+
+	a.Equal("90.80 GBP", pnr.Fare.Total.String())
+	a.Equal("30.20 GBP", pnr.Fare.Base.String())
+	a.Len(pnr.Tickets, 1)
+	a.Equal("1252106312810", pnr.Tickets[0].Number)
+}
@@ -0,0 +1,142 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bojanz/currency"
+)
+
+// ErrPartialOffer is returned when a partial offer is selected for an order; see Offer.IsPartial.
+var ErrPartialOffer = fmt.Errorf(
+	"duffel: partial offers cannot be booked, select fares with GetPartialOfferRequests and GetFullPartialOfferRequest",
+)
+
+// ErrPreflightFailed is returned by PreflightOrder when the order input does not match the current offer.
+var ErrPreflightFailed = fmt.Errorf("duffel: order preflight failed")
+
+// PreflightOrder re-fetches the selected offer and validates the input against it with ValidateAgainst,
+// without creating anything. Run it right before CreateOrder to check the order is likely to succeed.
+//
+// The current offer is returned along with ErrPreflightFailed, wrapping every problem found, when the
+// input does not match it.
+func (a *API) PreflightOrder(ctx context.Context, input CreateOrderInput) (*Offer, error) {
+	if len(input.SelectedOffers) != 1 {
+		return nil, fmt.Errorf("%w: exactly one selected offer is required", ErrPreflightFailed)
+	}
+
+	offer, err := a.GetOffer(
+		ctx, input.SelectedOffers[0], GetOfferParams{
+			ReturnAvailableServices: len(input.Services) > 0,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.ValidateAgainst(offer); err != nil {
+		return offer, fmt.Errorf("%w: %w", ErrPreflightFailed, err)
+	}
+	return offer, nil
+}
+
+// ValidateAgainst checks the input can book the given offer: the offer is not partial and has not
+// expired, the passengers match the offer's and their ages fit their passenger types, identity documents
+// are given and supported when required, and the payments cover the offer and services in its currency,
+// or are omitted for a hold order. It returns every problem found, joined, or ErrPartialOffer alone.
+func (in *CreateOrderInput) ValidateAgainst(offer *Offer) error {
+	if offer == nil || !slices.Contains(in.SelectedOffers, offer.ID) {
+		return fmt.Errorf("offer is not one of the selected offers")
+	}
+
+	if offer.IsPartial() {
+		return fmt.Errorf("%w: offer %s", ErrPartialOffer, offer.ID)
+	}
+
+	var errs []error
+	if offer.IsExpired() {
+		errs = append(errs, fmt.Errorf("offer %s expired at %s", offer.ID, offer.ExpiresAt.Format(time.RFC3339)))
+	}
+	errs = append(errs, in.validatePassengers(offer)...)
+	errs = append(errs, in.validatePayments(offer)...)
+	return errors.Join(errs...)
+}
+
+func (in *CreateOrderInput) validatePassengers(offer *Offer) []error {
+	var errs []error
+	for _, passenger := range offer.Passengers {
+		if !slices.ContainsFunc(in.Passengers, func(p OrderPassenger) bool { return p.ID == passenger.ID }) {
+			errs = append(errs, fmt.Errorf("passenger %s of offer %s is missing", passenger.ID, offer.ID))
+		}
+	}
+
+	for _, passenger := range in.Passengers {
+		if offer.PassengerType(passenger.ID) == "" {
+			errs = append(errs, fmt.Errorf("passenger %s is not on offer %s", passenger.ID, offer.ID))
+			continue
+		}
+		if !time.Time(passenger.BornOn).IsZero() {
+			if err := offer.ValidatePassengerAge(passenger.ID, passenger.BornOn); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if offer.PassengerIdentityDocumentsRequired && len(passenger.IdentityDocuments) == 0 {
+			errs = append(errs, fmt.Errorf("passenger %s requires an identity document", passenger.ID))
+		}
+		for _, document := range passenger.IdentityDocuments {
+			if !offer.SupportsDocumentType(document.Type) {
+				errs = append(
+					errs, fmt.Errorf("passenger %s has unsupported identity document %s", passenger.ID, document.Type),
+				)
+			}
+		}
+	}
+	return errs
+}
+
+func (in *CreateOrderInput) validatePayments(offer *Offer) []error {
+	if in.Type == OrderTypeHold {
+		var errs []error
+		if canHold, _ := offer.HoldInfo(); !canHold {
+			errs = append(errs, fmt.Errorf("offer %s requires instant payment", offer.ID))
+		}
+		if len(in.Payments) > 0 {
+			errs = append(errs, fmt.Errorf("hold orders are paid later and must not include payments"))
+		}
+		return errs
+	}
+
+	required, err := in.ComputeRequiredPayment(offer)
+	if err != nil {
+		return []error{err}
+	}
+
+	paid, err := currency.NewAmount("0", required.CurrencyCode())
+	if err != nil {
+		return []error{err}
+	}
+	for _, payment := range in.Payments {
+		amount, err := currency.NewAmount(payment.Amount, payment.Currency)
+		if err != nil {
+			return []error{fmt.Errorf("invalid payment amount: %w", err)}
+		}
+		if paid, err = paid.Add(amount); err != nil {
+			return []error{
+				fmt.Errorf("payment in %s does not match offer currency %s", payment.Currency, required.CurrencyCode()),
+			}
+		}
+	}
+	if !paid.Round().Equal(required.Round()) {
+		return []error{fmt.Errorf("payments total %s, expected %s", paid, required)}
+	}
+	return nil
+}
+
+var _ OrderPreflighter = (*API)(nil)
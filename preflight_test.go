@@ -0,0 +1,98 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestPreflightOrder(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/offers/off_00009htYpSCXrwaB9DnUm0").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-offers-off_00009htYpSCXrwaB9DnUm0.json")
+
+	client := New("duffel_test_123").(OrderPreflighter)
+	offer, err := client.PreflightOrder(
+		context.TODO(), CreateOrderInput{
+			Type:           OrderTypeInstant,
+			SelectedOffers: []string{"off_00009htYpSCXrwaB9DnUm0"},
+			Passengers:     []OrderPassenger{{ID: "pas_00009hj8USM7Ncg31cBCL"}},
+			Payments:       []PaymentCreateInput{{Type: PaymentMethodBalance, Amount: "45.00", Currency: "GBP"}},
+		},
+	)
+	a.ErrorIs(err, ErrPreflightFailed)
+	a.ErrorContains(err, "expired")
+	a.NotNil(offer)
+	a.True(gock.IsDone())
+}
+
+func TestCreateOrderInputValidateAgainst(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{
+		ID:                                 "off_123",
+		ExpiresAt:                          time.Now().Add(time.Hour),
+		RawTotalAmount:                     "45.00",
+		RawTotalCurrency:                   "GBP",
+		Passengers:                         []OfferRequestPassenger{{ID: "pas_1", Type: PassengerTypeAdult}},
+		PassengerIdentityDocumentsRequired: true,
+		SupportedPassengerIdentityDocumentTypes: []PassengerIdentityDocumentType{
+			PassengerIdentityDocumentTypePassport,
+		},
+	}
+	input := CreateOrderInput{
+		Type:           OrderTypeInstant,
+		SelectedOffers: []string{"off_123"},
+		Passengers: []OrderPassenger{
+			{
+				ID:                "pas_1",
+				BornOn:            Date(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+				IdentityDocuments: []IdentityDocument{{Type: PassengerIdentityDocumentTypePassport}},
+			},
+		},
+		Payments: []PaymentCreateInput{{Type: PaymentMethodBalance, Amount: "45.00", Currency: "GBP"}},
+	}
+	a.NoError(input.ValidateAgainst(offer))
+
+	input.Payments[0].Amount = "40.00"
+	input.Passengers[0].IdentityDocuments = nil
+	input.Passengers = append(input.Passengers, OrderPassenger{ID: "pas_unknown"})
+	err := input.ValidateAgainst(offer)
+	a.ErrorContains(err, "payments total 40.00 GBP, expected 45.00 GBP")
+	a.ErrorContains(err, "passenger pas_1 requires an identity document")
+	a.ErrorContains(err, "passenger pas_unknown is not on offer off_123")
+
+	hold := CreateOrderInput{
+		Type:           OrderTypeHold,
+		SelectedOffers: []string{"off_123"},
+		Passengers:     []OrderPassenger{{ID: "pas_1", IdentityDocuments: []IdentityDocument{{Type: PassengerIdentityDocumentTypePassport}}}},
+	}
+	a.NoError(hold.ValidateAgainst(offer))
+	offer.PaymentRequirements.RequiresInstantPayment = true
+	a.ErrorContains(hold.ValidateAgainst(offer), "requires instant payment")
+}
+
+func TestCreateOrderInputValidateAgainstPartialOffer(t *testing.T) {
+	a := assert.New(t)
+
+	offer := &Offer{ID: "off_partial", Partial: true, ExpiresAt: time.Now().Add(time.Hour)}
+	a.True(offer.IsPartial())
+
+	input := CreateOrderInput{Type: OrderTypeHold, SelectedOffers: []string{"off_partial"}}
+	a.ErrorIs(input.ValidateAgainst(offer), ErrPartialOffer)
+}
@@ -0,0 +1,153 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/bojanz/currency"
+)
+
+// RankWeights are the coefficients used by RankOffers. Each criterion is normalised to [0, 1]
+// across the ranked offers before being weighted, so coefficients are comparable with each other.
+// A zero weight ignores the criterion.
+type RankWeights struct {
+	// Price weights the offer total amount. Amounts are compared as numbers, regardless of currency.
+	// Offers whose total amount cannot be parsed are ranked last.
+	Price float64
+	// Duration weights the total duration of the offer's slices.
+	Duration float64
+	// Stops weights the total number of connections across the offer's slices.
+	Stops float64
+	// Carrier weights offers whose owner is not one of PreferredCarriers.
+	Carrier float64
+	// PreferredCarriers are the IATA codes of the preferred owning airlines.
+	PreferredCarriers []string
+}
+
+type offerCriteria struct {
+	price, duration, stops, carrier float64
+	// priced is false when the offer total amount cannot be parsed.
+	priced bool
+}
+
+// RankOffers returns the offers sorted from best to worst weighted score. Ties keep their original order.
+// Nil offers are dropped and the input slice is left untouched.
+func RankOffers(offers []*Offer, weights RankWeights) []*Offer {
+	ranked := make([]*Offer, 0, len(offers))
+	criteria := make([]offerCriteria, 0, len(offers))
+	for _, offer := range offers {
+		if offer == nil {
+			continue
+		}
+		ranked = append(ranked, offer)
+		criteria = append(criteria, newOfferCriteria(offer, weights.PreferredCarriers))
+	}
+
+	priced := slices.DeleteFunc(slices.Clone(criteria), func(c offerCriteria) bool { return !c.priced })
+	price := normaliser(priced, func(c offerCriteria) float64 { return c.price })
+	duration := normaliser(criteria, func(c offerCriteria) float64 { return c.duration })
+	stops := normaliser(criteria, func(c offerCriteria) float64 { return c.stops })
+
+	scores := make([]float64, len(ranked))
+	for i, c := range criteria {
+		scores[i] = weights.Price*price(c.price) +
+			weights.Duration*duration(c.duration) +
+			weights.Stops*stops(c.stops) +
+			weights.Carrier*c.carrier
+	}
+
+	indices := make([]int, len(ranked))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(
+		indices, func(i, j int) bool {
+			if ci, cj := criteria[indices[i]], criteria[indices[j]]; ci.priced != cj.priced {
+				return ci.priced
+			}
+			return scores[indices[i]] < scores[indices[j]]
+		},
+	)
+
+	sorted := make([]*Offer, len(ranked))
+	for i, index := range indices {
+		sorted[i] = ranked[index]
+	}
+	return sorted
+}
+
+func newOfferCriteria(offer *Offer, preferredCarriers []string) offerCriteria {
+	var c offerCriteria
+	if amount, err := currency.NewAmount(offer.RawTotalAmount, offer.RawTotalCurrency); err == nil {
+		if price, err := strconv.ParseFloat(amount.Number(), 64); err == nil {
+			c.price, c.priced = price, true
+		}
+	}
+	c.duration = offer.TotalDuration().Seconds()
+	for _, slice := range offer.Slices {
+		c.stops += float64(slice.ConnectionCount())
+	}
+	if !slices.Contains(preferredCarriers, offer.Owner.IATACode) {
+		c.carrier = 1
+	}
+	return c
+}
+
+// normaliser returns a function mapping a criterion value to [0, 1] relative to the range of values.
+func normaliser(criteria []offerCriteria, value func(offerCriteria) float64) func(float64) float64 {
+	if len(criteria) == 0 {
+		return func(float64) float64 { return 0 }
+	}
+
+	lowest, highest := value(criteria[0]), value(criteria[0])
+	for _, c := range criteria[1:] {
+		lowest = min(lowest, value(c))
+		highest = max(highest, value(c))
+	}
+
+	return func(v float64) float64 {
+		if highest == lowest {
+			return 0
+		}
+		return (v - lowest) / (highest - lowest)
+	}
+}
+
+type (
+	// TopOffersOption configures TopOffers.
+	TopOffersOption func(*topOffersOptions)
+
+	topOffersOptions struct {
+		skipExpired bool
+	}
+)
+
+// WithoutExpiredOffers makes TopOffers skip offers that can no longer be booked.
+func WithoutExpiredOffers() TopOffersOption {
+	return func(o *topOffersOptions) {
+		o.skipExpired = true
+	}
+}
+
+// TopOffers returns at most n offers, cheapest first.
+func TopOffers(offers []*Offer, n int, opts ...TopOffersOption) []*Offer {
+	options := &topOffersOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.skipExpired {
+		offers = FilterValidOffers(offers)
+	}
+
+	ranked := RankOffers(offers, RankWeights{Price: 1})
+	if len(ranked) > n {
+		ranked = ranked[:max(n, 0)]
+	}
+	return ranked
+}
@@ -0,0 +1,76 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankOffers(t *testing.T) {
+	a := assert.New(t)
+
+	newOffer := func(id, amount, owner string, duration time.Duration, segments int) *Offer {
+		return &Offer{
+			ID:               id,
+			RawTotalAmount:   amount,
+			RawTotalCurrency: "GBP",
+			Owner:            Airline{IATACode: owner},
+			Slices: []Slice{
+				{Duration: Duration(duration), Segments: make([]Flight, segments)},
+			},
+		}
+	}
+
+	cheap := newOffer("cheap", "100.00", "U2", 8*time.Hour, 3)
+	fast := newOffer("fast", "300.00", "BA", 2*time.Hour, 1)
+	middle := newOffer("middle", "200.00", "AF", 4*time.Hour, 2)
+	offers := []*Offer{middle, fast, nil, cheap}
+
+	ids := func(offers []*Offer) []string {
+		result := make([]string, len(offers))
+		for i, offer := range offers {
+			result[i] = offer.ID
+		}
+		return result
+	}
+
+	a.Equal([]string{"cheap", "middle", "fast"}, ids(RankOffers(offers, RankWeights{Price: 1})))
+	a.Equal([]string{"fast", "middle", "cheap"}, ids(RankOffers(offers, RankWeights{Duration: 1, Stops: 1})))
+	a.Equal(
+		[]string{"middle", "cheap", "fast"},
+		ids(RankOffers(offers, RankWeights{Price: 1, Carrier: 2, PreferredCarriers: []string{"AF"}})),
+	)
+	a.Equal([]string{"middle", "fast", "cheap"}, ids(RankOffers(offers, RankWeights{})))
+	a.Equal([]*Offer{middle, fast, nil, cheap}, offers)
+	a.Empty(RankOffers(nil, RankWeights{Price: 1}))
+
+	// An offer whose total cannot be parsed is ranked last rather than as the cheapest.
+	malformed := newOffer("malformed", "free", "BA", 2*time.Hour, 1)
+	a.Equal(
+		[]string{"cheap", "middle", "fast", "malformed"},
+		ids(RankOffers([]*Offer{malformed, middle, fast, cheap}, RankWeights{Price: 1})),
+	)
+}
+
+func TestTopOffers(t *testing.T) {
+	a := assert.New(t)
+
+	expired := &Offer{ID: "expired", RawTotalAmount: "50.00", RawTotalCurrency: "GBP", ExpiresAt: time.Now().Add(-time.Minute)}
+	cheap := &Offer{ID: "cheap", RawTotalAmount: "100.00", RawTotalCurrency: "GBP", ExpiresAt: time.Now().Add(time.Hour)}
+	pricey := &Offer{ID: "pricey", RawTotalAmount: "200.00", RawTotalCurrency: "GBP", ExpiresAt: time.Now().Add(time.Hour)}
+	offers := []*Offer{pricey, expired, cheap}
+
+	a.Equal([]*Offer{expired, cheap}, TopOffers(offers, 2))
+	a.Equal([]*Offer{cheap, pricey}, TopOffers(offers, 2, WithoutExpiredOffers()))
+	a.Equal([]*Offer{cheap, pricey}, TopOffers(offers, 10, WithoutExpiredOffers()))
+	a.Empty(TopOffers(offers, 0))
+
+	a.True(expired.IsExpired())
+	a.False(cheap.IsExpired())
+	a.Equal([]*Offer{pricey, cheap}, FilterValidOffers(append(offers, nil)))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -17,6 +18,22 @@ type (
 		Remaining int
 		ResetAt   time.Time
 		Period    time.Duration
+		// RetryAfter is the delay requested by the Retry-After header, zero when it is absent.
+		// Prefer it over ResetAt when set.
+		RetryAfter time.Duration
+	}
+
+	// throttle paces requests so that the quota remaining in the rate limit window lasts until it resets.
+	// Its window is kept relative to the local clock, so that clock skew with the API does not matter.
+	throttle struct {
+		mu        sync.Mutex
+		known     bool
+		remaining int
+		resetAt   time.Time
+		// next is the earliest time the next request may be sent.
+		next time.Time
+		// backgroundReserve is the quota background requests leave to other requests.
+		backgroundReserve int
 	}
 )
 
@@ -58,6 +75,103 @@ func parseRateLimit(resp *http.Response) (*RateLimit, error) {
 	}
 
 	rl.Period = rl.ResetAt.Sub(date)
+	rl.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), date)
 
 	return rl, nil
 }
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds or an HTTP date,
+// returning the delay from now. A date in the past is a zero delay.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	for _, format := range append([]string{http.TimeFormat}, headerTimeFormats...) {
+		if at, err := time.Parse(format, header); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+	return 0, false
+}
+
+// responseDate returns the Date header of the response, or the current time when it is missing.
+func responseDate(resp *http.Response) time.Time {
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		return date
+	}
+	return time.Now()
+}
+
+// update records the rate limit reported by a response received at now.
+func (t *throttle) update(now time.Time, rateLimit *RateLimit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.known = true
+	t.remaining = rateLimit.Remaining
+	t.resetAt = now.Add(max(rateLimit.Period, rateLimit.RetryAfter))
+}
+
+// seed starts pacing from a rate limit reported earlier, unless its window has reset at now.
+func (t *throttle) seed(now time.Time, rateLimit *RateLimit) {
+	if rateLimit == nil || !now.Before(rateLimit.ResetAt) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.known = true
+	t.remaining = rateLimit.Remaining
+	t.resetAt = rateLimit.ResetAt
+}
+
+// idle reports whether the rate limit window has reset at now, leaving nothing to pace. A throttle
+// that has not received a rate limit yet is not idle, as its first request may still be in flight.
+func (t *throttle) idle(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.known && !now.Before(t.resetAt)
+}
+
+// reserve returns how long to wait before sending a request of the given priority at now.
+// The remaining quota is spread evenly until the window resets and, once exhausted, requests wait
+// for the reset. Interactive requests are not spread, and background requests wait for the reset
+// as soon as the quota is down to the reserve kept for other requests.
+func (t *throttle) reserve(now time.Time, priority Priority) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.known || !now.Before(t.resetAt) {
+		return 0
+	}
+
+	switch {
+	case priority <= PriorityBackground && t.remaining <= t.backgroundReserve:
+		return t.resetAt.Sub(now)
+	case priority >= PriorityInteractive && t.remaining > 0:
+		t.remaining--
+		return 0
+	}
+
+	slot := now
+	if t.remaining <= 0 {
+		slot = t.resetAt
+	}
+	if t.next.After(slot) {
+		slot = t.next
+	}
+
+	t.next = slot
+	if t.remaining > 0 && slot.Before(t.resetAt) {
+		t.next = slot.Add(t.resetAt.Sub(slot) / time.Duration(t.remaining))
+		t.remaining--
+	}
+	return slot.Sub(now)
+}
@@ -0,0 +1,121 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRateLimitThrottling(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	responses := []struct {
+		remaining int
+		date      time.Time
+	}{
+		{remaining: 2, date: date},
+		{remaining: 1, date: date},
+		{remaining: 0, date: date.Add(5 * time.Second)},
+		{remaining: 4, date: date.Add(10 * time.Second)},
+	}
+	for _, r := range responses {
+		gock.New("https://api.duffel.com").
+			Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", strconv.Itoa(r.remaining)).
+			SetHeader("Ratelimit-Reset", date.Add(10*time.Second).Format(time.RFC1123)).
+			SetHeader("Date", r.date.Format(time.RFC1123)).
+			File("fixtures/200-get-airline.json")
+	}
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRateLimitThrottling(), WithClock(clock))
+	for range responses {
+		_, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+		a.NoError(err)
+	}
+	a.True(gock.IsDone())
+
+	// The second request goes right away, the third is spaced to spread the last request of the
+	// quota over the window, and the fourth waits for the window to reset.
+	a.Equal([]time.Duration{5 * time.Second, 5 * time.Second}, clock.Sleeps())
+}
+
+func TestRateLimitWithoutThrottling(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", time.Now().Add(10*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	client := New("duffel_test_123")
+	_, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+	a.True(IsErrorCode(err, RateLimitExceeded))
+}
+
+func TestThrottlePriorities(t *testing.T) {
+	a := assert.New(t)
+
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	throttle := &throttle{backgroundReserve: 2}
+	throttle.update(now, &RateLimit{Limit: 5, Remaining: 3, Period: 9 * time.Second})
+
+	// Above the reserve, background requests are paced like the others.
+	a.Equal(time.Duration(0), throttle.reserve(now, PriorityBackground))
+	// Down to the reserve, they wait for the next window.
+	a.Equal(9*time.Second, throttle.reserve(now, PriorityBackground))
+	// Interactive requests use the reserve without waiting for their turn.
+	a.Equal(time.Duration(0), throttle.reserve(now, PriorityInteractive))
+	// Normal requests keep the pacing.
+	a.Equal(3*time.Second, throttle.reserve(now, PriorityNormal))
+	// Once the quota is exhausted, every request waits for the reset.
+	a.Equal(9*time.Second, throttle.reserve(now, PriorityInteractive))
+}
+
+func TestWithBackgroundReserve(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Times(3).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "1").
+		SetHeader("Ratelimit-Reset", date.Add(30*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithBackgroundReserve(1), WithClock(clock))
+	background := WithPriority(context.TODO(), PriorityBackground)
+	interactive := WithPriority(context.TODO(), PriorityInteractive)
+
+	_, err := client.GetAirline(background, "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	_, err = client.GetAirline(interactive, "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	a.Empty(clock.Sleeps())
+
+	_, err = client.GetAirline(background, "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	a.Equal([]time.Duration{30 * time.Second}, clock.Sleeps())
+}
@@ -0,0 +1,41 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import "regexp"
+
+var (
+	// redactedHeaders matches the values of headers carrying credentials.
+	redactedHeaders = regexp.MustCompile(`(?mi)^((?:Authorization|Proxy-Authorization|Cookie|Set-Cookie):[ \t]*)[^\r\n]*`)
+
+	// redactedFields matches the values of JSON fields holding card details, passenger contact
+	// details, identity documents and tokens, whether strings or numbers.
+	redactedFields = regexp.MustCompile(
+		`("(?:number|cvc|expiry_month|expiry_year|email|phone_number|unique_identifier|token|client_key)"\s*:\s*)` +
+			`(?:"(?:[^"\\]|\\.)*"|-?\d+)`,
+	)
+
+	// redactedNames matches the values of JSON fields holding passenger names.
+	redactedNames = regexp.MustCompile(`("(?:given_name|family_name)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	// redactedBirthDates matches the values of JSON fields holding birth dates.
+	redactedBirthDates = regexp.MustCompile(`("born_on"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// redactDump removes credentials and sensitive data from a dumped request or response, so that
+// debug output can be logged without leaking card numbers or passenger details.
+func redactDump(dump []byte) []byte {
+	dump = redactedHeaders.ReplaceAll(dump, []byte("${1}REDACTED"))
+	return redactedFields.ReplaceAll(dump, []byte(`${1}"REDACTED"`))
+}
+
+// redactBody removes sensitive data from a JSON request or response body before it is stored,
+// including passenger names and birth dates. Birth dates are replaced by a valid placeholder date
+// so that the body can still be decoded.
+func redactBody(body string) string {
+	body = redactedFields.ReplaceAllString(body, `${1}"REDACTED"`)
+	body = redactedNames.ReplaceAllString(body, `${1}"REDACTED"`)
+	return redactedBirthDates.ReplaceAllString(body, `${1}"1970-01-01"`)
+}
@@ -0,0 +1,37 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactDump(t *testing.T) {
+	a := assert.New(t)
+
+	dump := "POST /vault/cards HTTP/1.1\r\n" +
+		"Host: api.duffel.com\r\n" +
+		"Authorization: Bearer duffel_live_secret\r\n" +
+		"Content-Type: application/json\r\n" +
+		"\r\n" +
+		`{"data":{"number":"4242424242424242","cvc":"123","expiry_month":"03","expiry_year":"30",` +
+		`"name":"Amelia Earhart","multi_use":false,"passengers":[{"email":"amelia@duffel.com",` +
+		`"phone_number" : "+442080160509","identity_documents":[{"unique_identifier":"19KL56147"}]}],` +
+		`"flight":{"operating_carrier_flight_number":"1234"},"cvc_check":123}}`
+
+	expected := "POST /vault/cards HTTP/1.1\r\n" +
+		"Host: api.duffel.com\r\n" +
+		"Authorization: REDACTED\r\n" +
+		"Content-Type: application/json\r\n" +
+		"\r\n" +
+		`{"data":{"number":"REDACTED","cvc":"REDACTED","expiry_month":"REDACTED","expiry_year":"REDACTED",` +
+		`"name":"Amelia Earhart","multi_use":false,"passengers":[{"email":"REDACTED",` +
+		`"phone_number" : "REDACTED","identity_documents":[{"unique_identifier":"REDACTED"}]}],` +
+		`"flight":{"operating_carrier_flight_number":"1234"},"cvc_check":123}}`
+
+	a.Equal(expected, string(redactDump([]byte(dump))))
+}
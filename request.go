@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -16,29 +17,48 @@ import (
 
 func newInternalClient[Req any, Resp any](a *API) *client[Req, Resp] {
 	client := &client[Req, Resp]{
-		httpDoer: a.httpDoer,
-		options:  a.options,
-		APIToken: a.APIToken,
-		limiter:  rate.NewLimiter(rate.Every(1*time.Second), 5),
+		httpDoer:    a.httpDoer,
+		options:     a.options,
+		APIToken:    a.APIToken,
+		limiter:     rate.NewLimiter(rate.Every(1*time.Second), 5),
+		throttleFor: a.throttleFor,
 		afterResponse: []func(resp *http.Response){
 			func(resp *http.Response) {
-				a.lastRequestID = resp.Header.Get(RequestIDHeader)
+				a.setLastRequestID(resp.Header.Get(RequestIDHeader))
+			},
+			func(resp *http.Response) {
+				if rateLimit, err := parseRateLimit(resp); err == nil {
+					a.setRateLimit(rateLimit)
+				}
 			},
 		},
 	}
 
+	client.afterResponse = append(client.afterResponse, func(resp *http.Response) {
+		if client.throttle == nil {
+			return
+		}
+		if rateLimit, err := parseRateLimit(resp); err == nil {
+			client.throttle.update(a.options.Clock.Now(), rateLimit)
+		}
+	})
+
 	return client
 }
 
 func (c *client[Req, Resp]) Do(
 	ctx context.Context, resourceName string, method string, body *Req, opts ...RequestOption,
 ) (*http.Response, error) {
-	payload, err := encodePayload(body)
-	if err != nil {
-		return nil, err
-	}
+	ctx, span := c.startSpan(ctx, method, resourceName)
+	resp, err := c.do(ctx, resourceName, method, body, opts...)
+	c.endSpan(span, resp, err)
+	return resp, err
+}
 
-	err = c.limiter.Wait(ctx) // This is a blocking call. Honors the rate limit
+func (c *client[Req, Resp]) do(
+	ctx context.Context, resourceName string, method string, body *Req, opts ...RequestOption,
+) (*http.Response, error) {
+	payload, err := encodePayload(body)
 	if err != nil {
 		return nil, err
 	}
@@ -48,37 +68,88 @@ func (c *client[Req, Resp]) Do(
 		return nil, err
 	}
 
-	for _, afterResponse := range c.afterResponse {
-		afterResponse(resp)
+	c.logRateLimit(ctx, c.rateLimit)
+	return resp, nil
+}
+
+// wait blocks until the request may be sent: the limiter allows it and, when throttling, its turn has come.
+// It is called before every attempt of the request.
+func (c *client[Req, Resp]) wait(req *http.Request) error {
+	ctx := req.Context()
+	if err := c.waitForLimiter(ctx); err != nil {
+		return err
 	}
 
-	rateLimit, err := parseRateLimit(resp)
-	if err != nil {
-		return nil, err
+	if c.throttle != nil {
+		if delay := c.throttle.reserve(c.options.Clock.Now(), priorityFromContext(ctx)); delay > 0 {
+			c.log(ctx, slog.LevelInfo, "duffel: throttling request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Duration("delay", delay),
+			)
+			if err := c.options.Clock.Sleep(ctx, delay); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
+// updateRateLimit records the rate limit reported by a successful response and adapts the limiter to it.
+// Without throttling, a response exhausting the quota is turned into a rate limit error, as the next
+// request would be rejected, so that it is retried after the reset when retries are enabled. The throttle
+// instead makes the next request wait for the reset.
+func (c *client[Req, Resp]) updateRateLimit(resp *http.Response, rateLimit *RateLimit) error {
 	c.rateLimit = rateLimit
-	c.limiter.SetBurst(rateLimit.Limit)
-	c.limiter.SetLimit(rate.Every(rateLimit.Period))
-
-	if rateLimit.Remaining == 0 {
-		_, _ = io.Copy(io.Discard, resp.Body)
-		resp.Body.Close()
-		return nil, &DuffelError{
-			StatusCode: http.StatusTooManyRequests,
-			Retryable:  true,
-			Errors: []Error{
-				{
-					Type:  RateLimitError,
-					Title: "Rate limit exceeded",
-					Message: fmt.Sprintf(
-						"Rate limit exceeded, reset in: %s, current limit: %d", rateLimit.Period.String(),
-						rateLimit.Limit,
-					),
-					Code: RateLimitExceeded,
-				},
+	now := c.options.Clock.Now()
+	c.limiter.SetBurstAt(now, rateLimit.Limit)
+	c.limiter.SetLimitAt(now, rate.Every(rateLimit.Period))
+
+	if rateLimit.Remaining > 0 || c.throttle != nil {
+		return nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	retryAfter := rateLimit.Period
+	if rateLimit.RetryAfter > 0 {
+		retryAfter = rateLimit.RetryAfter
+	}
+	return &DuffelError{
+		StatusCode: http.StatusTooManyRequests,
+		Retryable:  true,
+		RetryAfter: retryAfter,
+		RateLimit:  rateLimit,
+		Errors: []Error{
+			{
+				Type:  RateLimitError,
+				Title: "Rate limit exceeded",
+				Message: fmt.Sprintf(
+					"Rate limit exceeded, reset in: %s, current limit: %d", retryAfter.String(), rateLimit.Limit,
+				),
+				Code: RateLimitExceeded,
 			},
-		}
+		},
 	}
-	return resp, nil
+}
+
+// waitForLimiter blocks until the limiter allows another request, using the configured clock.
+func (c *client[Req, Resp]) waitForLimiter(ctx context.Context) error {
+	now := c.options.Clock.Now()
+	reservation := c.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return fmt.Errorf("duffel: rate limit burst exceeded")
+	}
+
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+
+	if err := c.options.Clock.Sleep(ctx, delay); err != nil {
+		reservation.CancelAt(c.options.Clock.Now())
+		return err
+	}
+	return nil
 }
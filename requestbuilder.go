@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gorilla/schema"
@@ -121,6 +120,15 @@ func (r *RequestBuilder[Req, Resp]) WithOptions(opts ...RequestOption) *RequestB
 	return r
 }
 
+// Idempotent marks a request whose effect must not be repeated, e.g. booking an order or taking a payment.
+// When the client generates idempotency keys, the request is sent with one.
+func (r *RequestBuilder[Req, Resp]) Idempotent() *RequestBuilder[Req, Resp] {
+	if r.client.options.IdempotencyKeys {
+		r.requestOptions = append(r.requestOptions, withGeneratedIdempotencyKey())
+	}
+	return r
+}
+
 // Get sets the request method to GET and the request path to the given path. Global request options are applied.
 func (r *RequestBuilder[Req, Resp]) Get(path string, opts ...RequestOption) *RequestBuilder[Req, Resp] {
 	r.method = http.MethodGet
@@ -176,9 +184,15 @@ func (r *RequestBuilder[Req, Resp]) Patch(path string, body *Req, opts ...Reques
 
 // Iter finalizes the request and returns an iterator over the response.
 func (r *RequestBuilder[Req, Resp]) Iter(ctx context.Context) *Iter[Resp] {
-	return GetIter(
+	return r.IterAfter(ctx, "")
+}
+
+// IterAfter finalizes the request and returns an iterator over the response, starting from the
+// page at the given cursor, as returned by Iter.After.
+func (r *RequestBuilder[Req, Resp]) IterAfter(ctx context.Context, cursor string) *Iter[Resp] {
+	return GetIterAfter(
 		func(lastMeta *ListMeta) (*List[Resp], error) {
-			ctx, cancel := context.WithDeadline(ctx, time.Now().Add(r.client.options.Timeout))
+			ctx, cancel := r.withTimeout(ctx)
 			defer cancel()
 
 			list := new(List[Resp])
@@ -197,14 +211,14 @@ func (r *RequestBuilder[Req, Resp]) Iter(ctx context.Context) *Iter[Resp] {
 			list.SetItems(container.Data)
 			list.setRequestID(response.Header.Get(RequestIDHeader))
 			return list, nil
-		},
+		}, cursor,
 	)
 }
 
 // Slice finalizes the request and returns the first page of items as a slice along with the error.
 // This is only needed for endpoints without pagination, such as place suggestions.
 func (r *RequestBuilder[Req, Resp]) Slice(ctx context.Context) ([]*Resp, error) {
-	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(r.client.options.Timeout))
+	ctx, cancel := r.withTimeout(ctx)
 	defer cancel()
 
 	response, err := r.makeRequest(ctx)
@@ -229,7 +243,7 @@ func (r *RequestBuilder[Req, Resp]) Single(ctx context.Context) (*Resp, error) {
 
 // SingleWithResponse finalizes the request and returns the decoded response along with the HTTP status code.
 func (r *RequestBuilder[Req, Resp]) SingleWithResponse(ctx context.Context) (*Resp, int, error) {
-	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(r.client.options.Timeout))
+	ctx, cancel := r.withTimeout(ctx)
 	defer cancel()
 
 	response, err := r.makeRequest(ctx)
@@ -248,7 +262,7 @@ func (r *RequestBuilder[Req, Resp]) SingleWithResponse(ctx context.Context) (*Re
 // Empty finalizes the request and returns an error if the request fails.
 // It is to be used for requests that are expected to return no data.
 func (r *RequestBuilder[Req, Resp]) Empty(ctx context.Context) error {
-	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(r.client.options.Timeout))
+	ctx, cancel := r.withTimeout(ctx)
 	defer cancel()
 
 	_, err := r.makeRequest(ctx)
@@ -259,6 +273,15 @@ func (r *RequestBuilder[Req, Resp]) Empty(ctx context.Context) error {
 	return nil
 }
 
+// withTimeout bounds a request by the timeout of the call, when set with WithCallTimeout, or else of the client.
+func (r *RequestBuilder[Req, Resp]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := r.client.options.Timeout
+	if d, ok := callTimeoutFromContext(ctx); ok {
+		timeout = d
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (r *RequestBuilder[Req, Resp]) makeRequest(ctx context.Context, opts ...RequestOption) (*http.Response, error) {
 	return r.client.Do(ctx, r.resourcePath, r.method, r.body, append(r.requestOptions, opts...)...)
 }
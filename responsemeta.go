@@ -0,0 +1,51 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type responseMetaKey struct{}
+
+// ResponseMeta describes the response to a call, including its Duffel request ID, to quote when
+// contacting Duffel support. Unlike LastRequestID, it is not shared with concurrent calls.
+type ResponseMeta struct {
+	// mu guards the recording of responses, which may arrive concurrently.
+	mu sync.Mutex
+
+	RequestID  string
+	StatusCode int
+	Header     http.Header
+}
+
+// WithResponseMeta returns a context that records the response to the calls made with it in meta,
+// the latest response winning, e.g. the last page of a list or the last attempt of a retried call.
+// It is filled in for error responses too. Concurrent calls may share the context, e.g. through
+// CreateOfferRequests, in which case meta holds whichever response was recorded last; read it once the
+// calls have returned.
+//
+//	ctx, meta := duffel.WithResponseMeta(ctx)
+//	order, err := client.CreateOrder(ctx, input)
+//	log.Printf("request ID: %s", meta.RequestID)
+func WithResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+// recordResponseMeta records the response in the ResponseMeta of the context, if any.
+func recordResponseMeta(ctx context.Context, resp *http.Response) {
+	meta, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if !ok {
+		return
+	}
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	meta.RequestID = resp.Header.Get(RequestIDHeader)
+	meta.StatusCode = resp.StatusCode
+	meta.Header = resp.Header.Clone()
+}
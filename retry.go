@@ -0,0 +1,78 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultMaxBackoff = 30 * time.Second
+
+// RetryPolicy controls how failed requests are retried. Only idempotent requests are retried:
+// GET, HEAD, OPTIONS, PUT and DELETE requests, and any request carrying an Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every attempt, with jitter.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts, 30 seconds by default.
+	MaxBackoff time.Duration
+}
+
+// next reports whether the request should be attempted again after the given attempt failed
+// with err, and how long to wait before doing so.
+func (p *RetryPolicy) next(req *http.Request, attempt int, err error) (time.Duration, bool) {
+	if p == nil || err == nil || attempt >= p.MaxAttempts || req.Context().Err() != nil {
+		return 0, false
+	}
+	if !isIdempotent(req) {
+		return 0, false
+	}
+
+	var derr *DuffelError
+	var uerr *url.Error
+	switch {
+	case errors.As(err, &derr):
+		if derr.StatusCode != http.StatusTooManyRequests && derr.StatusCode < 500 {
+			return 0, false
+		}
+		return max(p.backoff(attempt), derr.RetryAfter), true
+	case errors.As(err, &uerr):
+		// The request did not get a response, e.g. the connection was reset.
+		return p.backoff(attempt), true
+	default:
+		return 0, false
+	}
+}
+
+// backoff returns the exponential delay before retrying the given attempt, with the upper half jittered.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	d := p.Backoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
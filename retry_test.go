@@ -0,0 +1,223 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRetryIdempotentRequest(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		ReplyError(fmt.Errorf("connection reset by peer"))
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(3, time.Second), WithClock(clock))
+	airline, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	a.Equal("aln_00001876aqC8c5umZmrRds", airline.ID)
+	a.True(gock.IsDone())
+
+	sleeps := clock.Sleeps()
+	if a.Len(sleeps, 2) {
+		a.GreaterOrEqual(sleeps[0], 500*time.Millisecond)
+		a.LessOrEqual(sleeps[0], time.Second)
+		a.GreaterOrEqual(sleeps[1], time.Second)
+		a.LessOrEqual(sleeps[1], 2*time.Second)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Times(2).
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(2, time.Second), WithClock(clock))
+	_, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+	a.Error(err)
+	a.True(ErrIsRetryable(err))
+	a.True(gock.IsDone())
+	a.Len(clock.Sleeps(), 1)
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(429).
+		SetHeader("Retry-After", "20").
+		JSON(`{"errors": [{"type": "rate_limit_error", "code": "rate_limit_exceeded"}]}`)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(2, time.Second), WithClock(clock))
+	_, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	a.Equal([]time.Duration{20 * time.Second}, clock.Sleeps())
+}
+
+func TestRetrySkipsNonIdempotentRequest(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com/air/offer_requests").
+		MatchParam("return_offers", "false").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(3, time.Second), WithClock(clock))
+	_, err := client.CreateOfferRequest(context.TODO(), OfferRequestInput{ReturnOffers: false})
+	a.Error(err)
+	a.Empty(clock.Sleeps())
+}
+
+func TestRetryPostWithIdempotencyKey(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com/air/offer_requests").
+		MatchParam("return_offers", "false").
+		MatchHeader(IdempotencyKeyHeader, "^key_123$").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+	gock.New("https://api.duffel.com/air/offer_requests").
+		MatchParam("return_offers", "false").
+		MatchHeader(IdempotencyKeyHeader, "^key_123$").
+		BodyString(`"passengers"`).
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		File("fixtures/200-get-offer-request.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(2, time.Second), WithClock(clock))
+	ctx := WithCallOptions(context.TODO(), WithIdempotencyKey("key_123"))
+	_, err := client.CreateOfferRequest(ctx, OfferRequestInput{ReturnOffers: false})
+	a.NoError(err)
+	a.True(gock.IsDone())
+	a.Len(clock.Sleeps(), 1)
+}
+
+func TestRetryExhaustedRateLimit(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "0").
+		SetHeader("Ratelimit-Reset", date.Add(30*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", date.Add(90*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Add(30*time.Second).Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRetries(2, time.Second), WithClock(clock))
+	airline, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+	a.NoError(err)
+	a.Equal("aln_00001876aqC8c5umZmrRds", airline.ID)
+	a.True(gock.IsDone())
+	a.Equal([]time.Duration{30 * time.Second}, clock.Sleeps())
+}
+
+func TestRetryWaitsForThrottle(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "1").
+		SetHeader("Ratelimit-Reset", date.Add(10*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(503).
+		File("fixtures/503-service-unavailable.json")
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines/aln_00001876aqC8c5umZmrRds").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", date.Add(70*time.Second).Format(time.RFC1123)).
+		SetHeader("Date", date.Add(10*time.Second).Format(time.RFC1123)).
+		File("fixtures/200-get-airline.json")
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRateLimitThrottling(), WithRetries(2, time.Second), WithClock(clock))
+	for range 2 {
+		_, err := client.GetAirline(context.TODO(), "aln_00001876aqC8c5umZmrRds")
+		a.NoError(err)
+	}
+	a.True(gock.IsDone())
+
+	// The retry backs off, then waits for the window to reset as the last request of the quota was used.
+	sleeps := clock.Sleeps()
+	if a.Len(sleeps, 2) {
+		a.LessOrEqual(sleeps[0], time.Second)
+		a.Equal(10*time.Second, sleeps[0]+sleeps[1])
+	}
+}
+
+func TestRetryBackoffIsCapped(t *testing.T) {
+	a := assert.New(t)
+
+	p := &RetryPolicy{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 4 * time.Second}
+	for attempt := 1; attempt < 10; attempt++ {
+		a.LessOrEqual(p.backoff(attempt), 4*time.Second)
+	}
+	a.GreaterOrEqual(p.backoff(9), 2*time.Second)
+}
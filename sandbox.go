@@ -0,0 +1,78 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import "time"
+
+// SandboxScenario is a behaviour that the Duffel test environment triggers for a specific route.
+// See https://duffel.com/docs/guides/testing-your-integration
+type SandboxScenario string
+
+const (
+	// SandboxScenarioNoFlights returns no offers.
+	SandboxScenarioNoFlights SandboxScenario = "no_flights"
+	// SandboxScenarioHoldOrder returns offers that can be booked as hold orders.
+	SandboxScenarioHoldOrder SandboxScenario = "hold_order"
+	// SandboxScenarioConnectingFlights returns offers with connecting flights.
+	SandboxScenarioConnectingFlights SandboxScenario = "connecting_flights"
+	// SandboxScenarioNoBaggages returns offers without any included baggage.
+	SandboxScenarioNoBaggages SandboxScenario = "no_baggages"
+	// SandboxScenarioNoServices returns offers without any available services.
+	SandboxScenarioNoServices SandboxScenario = "no_services"
+	// SandboxScenarioOfferUnavailable returns offers that are no longer available when booked.
+	SandboxScenarioOfferUnavailable SandboxScenario = "offer_unavailable"
+	// SandboxScenarioPriceChange returns offers whose price changes when booked.
+	SandboxScenarioPriceChange SandboxScenario = "price_change"
+	// SandboxScenarioOrderCreationError returns offers that fail with an airline error when booked.
+	SandboxScenarioOrderCreationError SandboxScenario = "order_creation_error"
+	// SandboxScenarioInsufficientBalance returns offers that are too expensive for the test balance.
+	SandboxScenarioInsufficientBalance SandboxScenario = "insufficient_balance"
+	// SandboxScenarioCardPayment returns offers that can be paid for with a test card.
+	SandboxScenarioCardPayment SandboxScenario = "card_payment"
+	// SandboxScenarioAirlineInitiatedChange returns offers whose orders receive an airline-initiated change.
+	SandboxScenarioAirlineInitiatedChange SandboxScenario = "airline_initiated_change"
+)
+
+var sandboxScenarioRoutes = map[SandboxScenario][2]string{
+	SandboxScenarioNoFlights:              {"PVD", "RAI"},
+	SandboxScenarioHoldOrder:              {"JFK", "EWR"},
+	SandboxScenarioConnectingFlights:      {"LHR", "DXB"},
+	SandboxScenarioNoBaggages:             {"BTS", "MRU"},
+	SandboxScenarioNoServices:             {"BTS", "ABV"},
+	SandboxScenarioOfferUnavailable:       {"LGW", "LHR"},
+	SandboxScenarioPriceChange:            {"LHR", "STN"},
+	SandboxScenarioOrderCreationError:     {"LHR", "LGW"},
+	SandboxScenarioInsufficientBalance:    {"LGW", "STN"},
+	SandboxScenarioCardPayment:            {"LTN", "STN"},
+	SandboxScenarioAirlineInitiatedChange: {"LHR", "LTN"},
+}
+
+// Route returns the origin and destination IATA codes that trigger the scenario.
+// Both are empty for an unknown scenario.
+func (s SandboxScenario) Route() (origin, destination string) {
+	route := sandboxScenarioRoutes[s]
+	return route[0], route[1]
+}
+
+func (s SandboxScenario) String() string {
+	return string(s)
+}
+
+// SandboxOfferRequestInput returns a one-way economy offer request for a single adult, departing in
+// a week, on the route that triggers the given scenario in the Duffel test environment.
+func SandboxOfferRequestInput(scenario SandboxScenario) OfferRequestInput {
+	origin, destination := scenario.Route()
+	return OfferRequestInput{
+		CabinClass: CabinClassEconomy,
+		Passengers: []OfferRequestPassenger{{Type: PassengerTypeAdult}},
+		Slices: []OfferRequestSlice{
+			{
+				Origin:        origin,
+				Destination:   destination,
+				DepartureDate: Date(time.Now().AddDate(0, 0, 7)),
+			},
+		},
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxOfferRequestInput(t *testing.T) {
+	a := assert.New(t)
+
+	input := SandboxOfferRequestInput(SandboxScenarioPriceChange)
+	a.Len(input.Slices, 1)
+	a.Equal("LHR", input.Slices[0].Origin)
+	a.Equal("STN", input.Slices[0].Destination)
+	a.Len(input.Passengers, 1)
+	a.Equal(PassengerTypeAdult, input.Passengers[0].Type)
+
+	for scenario := range sandboxScenarioRoutes {
+		origin, destination := scenario.Route()
+		a.NotEmpty(origin, scenario.String())
+		a.NotEmpty(destination, scenario.String())
+	}
+
+	origin, destination := SandboxScenario("unknown").Route()
+	a.Empty(origin)
+	a.Empty(destination)
+}
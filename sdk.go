@@ -0,0 +1,50 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// SDKHeader identifies the client library sending a request, e.g.
+// "sdk=duffel-go; version=v2.4.0; go=go1.22.4; os=linux; arch=amd64", so that requests can be
+// correlated by client version in Duffel's logs. It is sent with every request, next to the user agent.
+const SDKHeader = "X-Duffel-Client"
+
+const modulePath = "github.com/thetreep/duffel/v2"
+
+// sdkHeaderValue returns the value of the SDK header, computed once.
+var sdkHeaderValue = sync.OnceValue(func() string {
+	return fmt.Sprintf(
+		"sdk=duffel-go; version=%s; go=%s; os=%s; arch=%s",
+		sdkVersion(), runtime.Version(), runtime.GOOS, runtime.GOARCH,
+	)
+})
+
+// sdkVersion returns the version of this module in the running binary, or "devel" when it is not
+// known, e.g. in its own tests or when replaced by a local copy.
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	module := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			module = dep
+		}
+	}
+	if module.Path != modulePath || module.Version == "" || module.Version == "(devel)" {
+		return "devel"
+	}
+	if module.Replace != nil && module.Replace.Version != "" {
+		return module.Replace.Version
+	}
+	return module.Version
+}
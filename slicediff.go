@@ -0,0 +1,121 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SliceDiff pairs a slice removed by an airline-initiated change with the slice that replaces it
+// on the same route. Removed or Added is nil when the change only removed or only added a slice.
+type SliceDiff struct {
+	// Origin and Destination are the IATA codes of the route, e.g. "LHR" and "JFK".
+	Origin      string
+	Destination string
+	Removed     *Slice
+	Added       *Slice
+	// DepartureDelta and ArrivalDelta are how much later the added slice departs and arrives than the
+	// removed one, negative when earlier. They are zero unless both slices are set and their times resolve.
+	DepartureDelta time.Duration
+	ArrivalDelta   time.Duration
+}
+
+// Diff pairs the removed and added slices of the change by route, in the order of the removed slices
+// followed by the added slices without a removed counterpart.
+func (c *AirlineInitiatedChanges) Diff() []SliceDiff {
+	diffs := make([]SliceDiff, 0, max(len(c.Removed), len(c.Added)))
+	paired := make([]bool, len(c.Added))
+	for i := range c.Removed {
+		removed := &c.Removed[i]
+		origin, destination := sliceRoute(removed)
+		diff := SliceDiff{Origin: origin, Destination: destination, Removed: removed}
+		for j := range c.Added {
+			if paired[j] {
+				continue
+			}
+			if addedOrigin, addedDestination := sliceRoute(&c.Added[j]); addedOrigin == origin &&
+				addedDestination == destination {
+				paired[j] = true
+				diff.Added = &c.Added[j]
+				diff.DepartureDelta, diff.ArrivalDelta = sliceDeltas(removed, diff.Added)
+				break
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	for j := range c.Added {
+		if !paired[j] {
+			origin, destination := sliceRoute(&c.Added[j])
+			diffs = append(diffs, SliceDiff{Origin: origin, Destination: destination, Added: &c.Added[j]})
+		}
+	}
+	return diffs
+}
+
+// String describes the change of the slice for a notification, e.g.
+// "LHR→JFK: BA117 departing 2024-05-01 10:00 → BA119 departing 2024-05-01 12:30 (+2h30m0s)".
+func (d SliceDiff) String() string {
+	route := fmt.Sprintf("%s→%s", d.Origin, d.Destination)
+	switch {
+	case d.Removed == nil:
+		return fmt.Sprintf("%s: added %s", route, describeSlice(d.Added))
+	case d.Added == nil:
+		return fmt.Sprintf("%s: cancelled %s", route, describeSlice(d.Removed))
+	case d.DepartureDelta > 0:
+		return fmt.Sprintf("%s: %s → %s (+%s)", route, describeSlice(d.Removed), describeSlice(d.Added), d.DepartureDelta)
+	case d.DepartureDelta < 0:
+		return fmt.Sprintf("%s: %s → %s (%s)", route, describeSlice(d.Removed), describeSlice(d.Added), d.DepartureDelta)
+	default:
+		return fmt.Sprintf("%s: %s → %s", route, describeSlice(d.Removed), describeSlice(d.Added))
+	}
+}
+
+// sliceRoute returns the IATA codes of the origin of the first segment and the destination of the last.
+func sliceRoute(slice *Slice) (string, string) {
+	if len(slice.Segments) == 0 {
+		if slice.BaseSlice == nil {
+			return "", ""
+		}
+		return slice.Origin.IATACode, slice.Destination.IATACode
+	}
+	return slice.Segments[0].Origin.IATACode, slice.Segments[len(slice.Segments)-1].Destination.IATACode
+}
+
+func sliceDeltas(removed, added *Slice) (time.Duration, time.Duration) {
+	if len(removed.Segments) == 0 || len(added.Segments) == 0 {
+		return 0, 0
+	}
+
+	var departure, arrival time.Duration
+	before, errBefore := removed.Segments[0].DepartingAt()
+	after, errAfter := added.Segments[0].DepartingAt()
+	if errBefore == nil && errAfter == nil {
+		departure = after.Sub(before)
+	}
+	before, errBefore = removed.Segments[len(removed.Segments)-1].ArrivingAt()
+	after, errAfter = added.Segments[len(added.Segments)-1].ArrivingAt()
+	if errBefore == nil && errAfter == nil {
+		arrival = after.Sub(before)
+	}
+	return departure, arrival
+}
+
+// describeSlice returns the flight numbers of the slice and its departure, e.g. "BA117 departing 2024-05-01 10:00".
+func describeSlice(slice *Slice) string {
+	if len(slice.Segments) == 0 {
+		return "slice " + slice.ID
+	}
+	flights := make([]string, 0, len(slice.Segments))
+	for _, segment := range slice.Segments {
+		flights = append(flights, segment.MarketingCarrier.IATACode+segment.MarketingCarrierFlightNumber)
+	}
+	description := strings.Join(flights, "/")
+	if departure, err := slice.Segments[0].DepartingAt(); err == nil {
+		description += " departing " + departure.Format("2006-01-02 15:04")
+	}
+	return description
+}
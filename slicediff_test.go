@@ -0,0 +1,55 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAirlineInitiatedChangesDiff(t *testing.T) {
+	a := assert.New(t)
+
+	flight := func(number, origin, destination, departing, arriving string) Flight {
+		return Flight{
+			MarketingCarrier:             Airline{IATACode: "BA"},
+			MarketingCarrierFlightNumber: number,
+			Origin:                       Location{IATACode: origin, TimeZone: "UTC"},
+			Destination:                  Location{IATACode: destination, TimeZone: "UTC"},
+			RawDepartingAt:               departing,
+			RawArrivingAt:                arriving,
+		}
+	}
+	change := AirlineInitiatedChanges{
+		Removed: []Slice{
+			{ID: "sli_out", Segments: []Flight{flight("117", "LHR", "JFK", "2024-05-01T10:00:00", "2024-05-01T13:00:00")}},
+			{ID: "sli_back", Segments: []Flight{flight("118", "JFK", "LHR", "2024-05-08T18:00:00", "2024-05-09T06:00:00")}},
+		},
+		Added: []Slice{
+			{ID: "sli_new", Segments: []Flight{flight("119", "LHR", "JFK", "2024-05-01T12:30:00", "2024-05-01T15:45:00")}},
+		},
+	}
+
+	diffs := change.Diff()
+	if !a.Len(diffs, 2) {
+		return
+	}
+
+	a.Equal("LHR", diffs[0].Origin)
+	a.Equal("JFK", diffs[0].Destination)
+	a.Equal("sli_out", diffs[0].Removed.ID)
+	a.Equal("sli_new", diffs[0].Added.ID)
+	a.Equal(150*time.Minute, diffs[0].DepartureDelta)
+	a.Equal(165*time.Minute, diffs[0].ArrivalDelta)
+	a.Equal(
+		"LHR→JFK: BA117 departing 2024-05-01 10:00 → BA119 departing 2024-05-01 12:30 (+2h30m0s)",
+		diffs[0].String(),
+	)
+
+	a.Nil(diffs[1].Added)
+	a.Equal("JFK→LHR: cancelled BA118 departing 2024-05-08 18:00", diffs[1].String())
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type callTokenKey struct{}
+
+type (
+	// TokenProvider resolves the API token of a request from its context, e.g. the token of the
+	// Duffel organisation of the tenant the request is made for.
+	TokenProvider func(ctx context.Context) (string, error)
+
+	// TokenSource supplies the API token, like oauth2.TokenSource, so that a token kept in a secret
+	// manager can rotate without recreating the client. It is called for every request: wrap sources
+	// that are slow or rate limited with ReuseTokenSource.
+	TokenSource interface {
+		Token() (string, error)
+	}
+
+	// TokenSourceFunc is a function implementing TokenSource.
+	TokenSourceFunc func() (string, error)
+
+	reuseTokenSource struct {
+		source TokenSource
+		ttl    time.Duration
+		now    func() time.Time
+
+		mu        sync.Mutex
+		token     string
+		expiresAt time.Time
+	}
+)
+
+func (f TokenSourceFunc) Token() (string, error) {
+	return f()
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func() (string, error) {
+		return token, nil
+	})
+}
+
+// ReuseTokenSource returns a TokenSource that reuses the token of source for ttl before asking
+// source again, so that a rotated token is picked up within ttl. Errors are not cached.
+func ReuseTokenSource(source TokenSource, ttl time.Duration) TokenSource {
+	return &reuseTokenSource{source: source, ttl: ttl, now: time.Now}
+}
+
+func (s *reuseTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, err := s.source.Token()
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiresAt = token, now.Add(s.ttl)
+	return token, nil
+}
+
+// WithToken returns a context whose requests are made with the given API token instead of the
+// client's, e.g. to act for another Duffel organisation without creating a client per tenant.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, callTokenKey{}, token)
+}
+
+// resolveToken returns the API token of a request: the token of the call set with WithToken,
+// else the token of the provider set with WithTokenProvider, else the token of the client.
+func resolveToken(ctx context.Context, apiToken string, options *Options) (string, error) {
+	if token, ok := ctx.Value(callTokenKey{}).(string); ok && token != "" {
+		return token, nil
+	}
+
+	if options.TokenProvider != nil {
+		token, err := options.TokenProvider(ctx)
+		if err != nil {
+			return "", fmt.Errorf("duffel: failed to resolve API token: %w", err)
+		}
+		return token, nil
+	}
+
+	return apiToken, nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type tenantKey struct{}
+
+func TestWithToken(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		MatchHeader("Authorization", "^Bearer duffel_live_tenant$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"meta": {"limit": 1, "after": null}, "data": [{"id": "arl_1"}]}`)
+
+	client := New("duffel_test_123").(IdentityClient)
+	me, err := client.GetMe(WithToken(context.TODO(), "duffel_live_tenant"))
+	a.NoError(err)
+	a.True(me.LiveMode)
+	a.True(gock.IsDone())
+}
+
+func TestWithTokenProvider(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	for _, token := range []string{"duffel_test_acme", "duffel_test_override"} {
+		gock.New("https://api.duffel.com").
+			Get("/air/orders/ord_123").
+			MatchHeader("Authorization", "^Bearer "+token+"$").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", "5").
+			SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+			SetHeader("Date", time.Now().Format(time.RFC1123)).
+			File("fixtures/200-get-order.json")
+	}
+
+	tokens := map[string]string{"acme": "duffel_test_acme"}
+	client := New("", WithTokenProvider(func(ctx context.Context) (string, error) {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		token, ok := tokens[tenant]
+		if !ok {
+			return "", fmt.Errorf("unknown tenant %q", tenant)
+		}
+		return token, nil
+	}))
+
+	ctx := context.WithValue(context.TODO(), tenantKey{}, "acme")
+	_, err := client.GetOrder(ctx, "ord_123")
+	a.NoError(err)
+
+	_, err = client.GetOrder(WithToken(ctx, "duffel_test_override"), "ord_123")
+	a.NoError(err)
+	a.True(gock.IsDone())
+
+	_, err = client.GetOrder(context.TODO(), "ord_123")
+	a.ErrorContains(err, `duffel: failed to resolve API token: unknown tenant ""`)
+}
+
+func TestWithTokenSource(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	for _, token := range []string{"duffel_test_v1", "duffel_test_v2"} {
+		gock.New("https://api.duffel.com").
+			Get("/air/orders/ord_123").
+			MatchHeader("Authorization", "^Bearer "+token+"$").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", "5").
+			SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+			SetHeader("Date", time.Now().Format(time.RFC1123)).
+			File("fixtures/200-get-order.json")
+	}
+
+	current := "duffel_test_v1"
+	client := New("", WithTokenSource(TokenSourceFunc(func() (string, error) {
+		return current, nil
+	})))
+
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+
+	current = "duffel_test_v2"
+	_, err = client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	a.True(gock.IsDone())
+}
+
+func TestGetMeReusesProvidedToken(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/airlines").
+		MatchHeader("Authorization", "^Bearer duffel_live_1$").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"meta": {"limit": 1, "after": null}, "data": [{"id": "arl_1"}]}`)
+
+	tokens := []string{"duffel_live_1", "duffel_test_2"}
+	client := New("", WithTokenProvider(func(context.Context) (string, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, nil
+	})).(IdentityClient)
+	me, err := client.GetMe(context.TODO())
+	a.NoError(err)
+	a.True(me.LiveMode)
+	a.True(gock.IsDone())
+}
+
+func TestRateLimitThrottlingPerToken(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for _, token := range []string{"duffel_test_acme", "duffel_test_globex", "duffel_test_acme"} {
+		gock.New("https://api.duffel.com").
+			Get("/air/orders/ord_123").
+			MatchHeader("Authorization", "^Bearer "+token+"$").
+			Reply(200).
+			SetHeader("Ratelimit-Limit", "5").
+			SetHeader("Ratelimit-Remaining", "0").
+			SetHeader("Ratelimit-Reset", date.Add(10*time.Second).Format(time.RFC1123)).
+			SetHeader("Date", date.Format(time.RFC1123)).
+			File("fixtures/200-get-order.json")
+	}
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRateLimitThrottling(), WithClock(clock))
+
+	// Exhausting the quota of a token does not delay the requests made with another token.
+	_, err := client.GetOrder(WithToken(context.TODO(), "duffel_test_acme"), "ord_123")
+	a.NoError(err)
+	_, err = client.GetOrder(WithToken(context.TODO(), "duffel_test_globex"), "ord_123")
+	a.NoError(err)
+	a.Empty(clock.Sleeps())
+
+	_, err = client.GetOrder(WithToken(context.TODO(), "duffel_test_acme"), "ord_123")
+	a.NoError(err)
+	a.Equal([]time.Duration{10 * time.Second}, clock.Sleeps())
+	a.True(gock.IsDone())
+}
+
+func TestThrottleForConcurrentFirstUse(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newFakeClock()
+	client := New("duffel_test_123", WithRateLimitThrottling(), WithClock(clock)).(*API)
+	tokens := []string{"duffel_test_acme", "duffel_test_globex", "duffel_test_initech"}
+
+	// Throttles are handed out concurrently before any response updates them: every caller of a
+	// token must pace on the same one, even while other tokens are first used.
+	throttles := make([][]*throttle, len(tokens))
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		throttles[i] = make([]*throttle, 10)
+		for j := range throttles[i] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				throttles[i][j] = client.throttleFor(token)
+			}()
+		}
+	}
+	wg.Wait()
+
+	for i, token := range tokens {
+		for _, got := range throttles[i] {
+			a.Same(client.throttleFor(token), got)
+		}
+	}
+
+	// Once its window has reset, the throttle of a token is dropped when another token is first used.
+	acme := client.throttleFor("duffel_test_acme")
+	acme.update(clock.Now(), &RateLimit{Limit: 5, Remaining: 0, Period: 10 * time.Second})
+	_ = clock.Sleep(context.TODO(), 10*time.Second)
+	client.throttleFor("duffel_test_hooli")
+	a.NotSame(acme, client.throttleFor("duffel_test_acme"))
+	a.Same(throttles[1][0], client.throttleFor("duffel_test_globex"))
+}
+
+func TestReuseTokenSource(t *testing.T) {
+	a := assert.New(t)
+
+	calls := 0
+	failing := false
+	source := ReuseTokenSource(TokenSourceFunc(func() (string, error) {
+		calls++
+		if failing {
+			return "", fmt.Errorf("secret manager unavailable")
+		}
+		return fmt.Sprintf("duffel_test_%d", calls), nil
+	}), time.Minute).(*reuseTokenSource)
+
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	source.now = func() time.Time { return now }
+
+	token, err := source.Token()
+	a.NoError(err)
+	a.Equal("duffel_test_1", token)
+
+	now = now.Add(30 * time.Second)
+	token, _ = source.Token()
+	a.Equal("duffel_test_1", token)
+
+	now = now.Add(time.Minute)
+	failing = true
+	_, err = source.Token()
+	a.Error(err)
+
+	failing = false
+	token, _ = source.Token()
+	a.Equal("duffel_test_3", token)
+	a.Equal(3, calls)
+
+	token, _ = StaticTokenSource("duffel_test_static").Token()
+	a.Equal("duffel_test_static", token)
+}
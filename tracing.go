@@ -0,0 +1,82 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/thetreep/duffel/v2"
+
+// Span attributes specific to Duffel, next to the standard HTTP client ones.
+const (
+	AttributeRequestID          = attribute.Key("duffel.request_id")
+	AttributeRateLimitLimit     = attribute.Key("duffel.ratelimit.limit")
+	AttributeRateLimitRemaining = attribute.Key("duffel.ratelimit.remaining")
+	AttributeRateLimitResetAt   = attribute.Key("duffel.ratelimit.reset_at")
+)
+
+// startSpan starts the span of an API call. Retries of the call are part of the same span.
+func (c *client[Req, Resp]) startSpan(ctx context.Context, method, resourceName string) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.URLPath(resourceName),
+	}
+	if u, err := url.Parse(c.options.Host); err == nil {
+		attributes = append(attributes, semconv.ServerAddress(u.Hostname()))
+	}
+
+	return c.options.TracerProvider.Tracer(tracerName).Start(
+		ctx, "Duffel "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+// endSpan records the outcome of an API call on its span and ends it.
+func (c *client[Req, Resp]) endSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+
+	var derr *DuffelError
+	switch {
+	case err == nil:
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(resp.StatusCode),
+			AttributeRequestID.String(resp.Header.Get(RequestIDHeader)),
+		)
+		setRateLimitAttributes(span, c.rateLimit)
+	case errors.As(err, &derr):
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(derr.StatusCode),
+			AttributeRequestID.String(derr.Meta.RequestID),
+		)
+		setRateLimitAttributes(span, derr.RateLimit)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+func setRateLimitAttributes(span trace.Span, rateLimit *RateLimit) {
+	if rateLimit == nil {
+		return
+	}
+	span.SetAttributes(
+		AttributeRateLimitLimit.Int(rateLimit.Limit),
+		AttributeRateLimitRemaining.Int(rateLimit.Remaining),
+		AttributeRateLimitResetAt.String(rateLimit.ResetAt.Format(time.RFC3339)),
+	)
+}
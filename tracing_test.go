@@ -0,0 +1,70 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	date := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("x-request-id", "FZW0H3HdJwKk5HMAAKxB").
+		SetHeader("Ratelimit-Limit", "60").
+		SetHeader("Ratelimit-Remaining", "59").
+		SetHeader("Ratelimit-Reset", date.Add(time.Minute).Format(time.RFC1123)).
+		SetHeader("Date", date.Format(time.RFC1123)).
+		File("fixtures/200-get-order.json")
+	gock.New("https://api.duffel.com/air/offer_requests").
+		Reply(400).
+		File("fixtures/400-bad-request.json")
+
+	recorder := tracetest.NewSpanRecorder()
+	client := New("duffel_test_123", WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	_, err := client.GetOrder(context.TODO(), "ord_123")
+	a.NoError(err)
+	_, err = client.CreateOfferRequest(context.TODO(), OfferRequestInput{})
+	a.Error(err)
+
+	spans := recorder.Ended()
+	if !a.Len(spans, 2) {
+		return
+	}
+
+	a.Equal("Duffel GET", spans[0].Name())
+	a.Equal(codes.Unset, spans[0].Status().Code)
+	a.Subset(spans[0].Attributes(), []attribute.KeyValue{
+		attribute.String("http.request.method", "GET"),
+		attribute.String("url.path", "/air/orders/ord_123"),
+		attribute.String("server.address", "api.duffel.com"),
+		attribute.Int("http.response.status_code", 200),
+		AttributeRequestID.String("FZW0H3HdJwKk5HMAAKxB"),
+		AttributeRateLimitLimit.Int(60),
+		AttributeRateLimitRemaining.Int(59),
+		AttributeRateLimitResetAt.String("2024-06-01T12:01:00Z"),
+	})
+
+	a.Equal("Duffel POST", spans[1].Name())
+	a.Equal(codes.Error, spans[1].Status().Code)
+	a.Subset(spans[1].Attributes(), []attribute.KeyValue{
+		attribute.Int("http.response.status_code", 400),
+		AttributeRequestID.String("FZW0H3HdJwKk5HMAAKxB"),
+	})
+}
@@ -0,0 +1,44 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// newTransport returns a clone of http.DefaultTransport with the pool settings applied.
+func (t *TransportConfig) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if t.MaxIdleConns > 0 {
+		transport.MaxIdleConns = t.MaxIdleConns
+	}
+
+	if t.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = t.MaxConnsPerHost
+		transport.MaxIdleConnsPerHost = t.MaxConnsPerHost
+	}
+
+	if t.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.IdleConnTimeout
+	}
+
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+
+	if t.TLSClientConfig != nil {
+		transport.TLSClientConfig = t.TLSClientConfig.Clone()
+	}
+
+	if t.DisableHTTP2 {
+		// A non-nil empty TLSNextProto disables the HTTP/2 upgrade of net/http.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return transport
+}
@@ -0,0 +1,116 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInterval = 1 * time.Second
+	defaultMaxWait      = 60 * time.Second
+)
+
+// ErrWaitTimeout is returned by pollers when the condition was not met within the maximum wait.
+var ErrWaitTimeout = fmt.Errorf("duffel: timed out waiting for condition")
+
+type (
+	// WaitOption configures how pollers such as WaitForOrder check for a condition.
+	WaitOption func(*waitOptions)
+
+	waitOptions struct {
+		interval time.Duration
+		maxWait  time.Duration
+		backoff  float64
+	}
+)
+
+// WithInterval sets the delay between two checks. Default is 1 second.
+func WithInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.interval = d
+	}
+}
+
+// WithMaxWait sets the maximum time to wait before giving up with ErrWaitTimeout. Default is 60 seconds.
+func WithMaxWait(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.maxWait = d
+	}
+}
+
+// WithBackoff multiplies the interval by factor after each check. Default is 1 (constant interval).
+func WithBackoff(factor float64) WaitOption {
+	return func(o *waitOptions) {
+		o.backoff = factor
+	}
+}
+
+func newWaitOptions(opts ...WaitOption) *waitOptions {
+	options := &waitOptions{
+		interval: defaultWaitInterval,
+		maxWait:  defaultMaxWait,
+		backoff:  1,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.backoff < 1 {
+		options.backoff = 1
+	}
+	return options
+}
+
+// waitUntil calls check until it returns true, an error, or the maximum wait is exceeded.
+// It is the polling core shared by all pollers.
+func (a *API) waitUntil(ctx context.Context, check func() (bool, error), opts ...WaitOption) error {
+	options := newWaitOptions(opts...)
+	clock := a.options.Clock
+	deadline := clock.Now().Add(options.maxWait)
+	interval := options.interval
+
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if clock.Now().Add(interval).After(deadline) {
+			return ErrWaitTimeout
+		}
+		if err := clock.Sleep(ctx, interval); err != nil {
+			return err
+		}
+		interval = time.Duration(float64(interval) * options.backoff)
+	}
+}
+
+// WaitForOrder polls the order until ready returns true, e.g. until its documents have been issued.
+func (a *API) WaitForOrder(
+	ctx context.Context, id string, ready func(*Order) bool, opts ...WaitOption,
+) (*Order, error) {
+	var order *Order
+	err := a.waitUntil(
+		ctx, func() (bool, error) {
+			var err error
+			order, err = a.GetOrder(ctx, id)
+			if err != nil {
+				return false, err
+			}
+			return ready(order), nil
+		}, opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+var _ OrderWaiter = (*API)(nil)
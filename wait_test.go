@@ -0,0 +1,90 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWaitUntil(t *testing.T) {
+	a := assert.New(t)
+	clock := newFakeClock()
+	api := New("duffel_test_123", WithClock(clock)).(*API)
+
+	calls := 0
+	err := api.waitUntil(
+		context.TODO(), func() (bool, error) {
+			calls++
+			return calls == 4, nil
+		}, WithInterval(time.Second), WithBackoff(2),
+	)
+	a.NoError(err)
+	a.Equal(4, calls)
+	a.Equal([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, clock.Sleeps())
+}
+
+func TestWaitUntilTimeout(t *testing.T) {
+	a := assert.New(t)
+	clock := newFakeClock()
+	api := New("duffel_test_123", WithClock(clock)).(*API)
+
+	err := api.waitUntil(
+		context.TODO(), func() (bool, error) {
+			return false, nil
+		}, WithInterval(10*time.Second), WithMaxWait(25*time.Second),
+	)
+	a.ErrorIs(err, ErrWaitTimeout)
+	a.Equal([]time.Duration{10 * time.Second, 10 * time.Second}, clock.Sleeps())
+}
+
+func TestWaitUntilError(t *testing.T) {
+	a := assert.New(t)
+	api := New("duffel_test_123", WithClock(newFakeClock())).(*API)
+
+	err := api.waitUntil(
+		context.TODO(), func() (bool, error) {
+			return false, fmt.Errorf("boom")
+		},
+	)
+	a.EqualError(err, "boom")
+}
+
+func TestWaitForOrder(t *testing.T) {
+	defer gock.Off()
+	a := assert.New(t)
+
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"data": {"id": "ord_123", "documents": []}}`)
+	gock.New("https://api.duffel.com").
+		Get("/air/orders/ord_123").
+		Reply(200).
+		SetHeader("Ratelimit-Limit", "5").
+		SetHeader("Ratelimit-Remaining", "5").
+		SetHeader("Ratelimit-Reset", time.Now().Format(time.RFC1123)).
+		SetHeader("Date", time.Now().Format(time.RFC1123)).
+		JSON(`{"data": {"id": "ord_123", "documents": [{"type": "electronic_ticket", "unique_identifier": "1252106312810"}]}}`)
+
+	client := New("duffel_test_123", WithClock(newFakeClock())).(OrderWaiter)
+	order, err := client.WaitForOrder(
+		context.TODO(), "ord_123", func(o *Order) bool {
+			return len(o.Documents) > 0
+		},
+	)
+	a.NoError(err)
+	a.Equal("1252106312810", order.Documents[0].UniqueIdentifier)
+	a.True(gock.IsDone())
+}
@@ -0,0 +1,207 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// WebhookSignatureHeader is the header carrying the signature of a webhook, e.g. "t=1616202842,v1=8aeb...".
+const WebhookSignatureHeader = "X-Duffel-Signature"
+
+const (
+	defaultWebhookTolerance = 5 * time.Minute
+	maxWebhookBodySize      = 1 << 20
+)
+
+var (
+	// ErrInvalidWebhookSignature is returned when a webhook is not signed with the expected secret,
+	// or was signed too long ago.
+	ErrInvalidWebhookSignature = fmt.Errorf("duffel: invalid webhook signature")
+
+	// ErrUnknownWebhookEvent is returned by Dispatcher.HandleRequest for an event type without a handler.
+	ErrUnknownWebhookEvent = fmt.Errorf("duffel: unknown webhook event type")
+)
+
+const (
+	WebhookEventPing                                WebhookEventType = "ping.triggered"
+	WebhookEventOrderCreated                        WebhookEventType = "order.created"
+	WebhookEventOrderCreationFailed                 WebhookEventType = "order.creation_failed"
+	WebhookEventOrderAirlineInitiatedChangeDetected WebhookEventType = "order.airline_initiated_change_detected"
+	WebhookEventOrderCancellationCreated            WebhookEventType = "order_cancellation.created"
+	WebhookEventOrderCancellationConfirmed          WebhookEventType = "order_cancellation.confirmed"
+)
+
+type (
+	WebhookEventType string
+
+	// WebhookEvent is the payload Duffel posts to a webhook endpoint.
+	WebhookEvent struct {
+		ID             string           `json:"id"`
+		Type           WebhookEventType `json:"type"`
+		LiveMode       bool             `json:"live_mode"`
+		IdempotencyKey string           `json:"idempotency_key"`
+		CreatedAt      time.Time        `json:"created_at"`
+		Data           struct {
+			// Object is the resource the event is about, e.g. an Order for order.created.
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+
+	// Dispatcher verifies webhook requests and dispatches their events to the handler registered
+	// for their type with HandleWebhook.
+	Dispatcher struct {
+		secret    string
+		tolerance time.Duration
+		clock     Clock
+		handlers  map[WebhookEventType]func(ctx context.Context, event *WebhookEvent) error
+	}
+
+	DispatcherOption func(*Dispatcher)
+)
+
+// WithSignatureTolerance sets how long after signing a webhook is accepted, 5 minutes by default.
+// Zero disables the check.
+func WithSignatureTolerance(tolerance time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.tolerance = tolerance
+	}
+}
+
+// WithDispatcherClock sets the clock used to check the signature tolerance.
+func WithDispatcherClock(clock Clock) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.clock = clock
+	}
+}
+
+// NewDispatcher returns a dispatcher verifying webhooks with the secret of the webhook endpoint.
+func NewDispatcher(secret string, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		secret:    secret,
+		tolerance: defaultWebhookTolerance,
+		clock:     systemClock{},
+		handlers:  make(map[WebhookEventType]func(ctx context.Context, event *WebhookEvent) error),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// HandleWebhook registers the handler of an event type, decoding the event object as T,
+// e.g. HandleWebhook(d, WebhookEventOrderCreated, func(ctx context.Context, e *WebhookEvent, o *Order) error {...}).
+func HandleWebhook[T any](
+	d *Dispatcher, eventType WebhookEventType, handler func(ctx context.Context, event *WebhookEvent, object *T) error,
+) {
+	d.handlers[eventType] = func(ctx context.Context, event *WebhookEvent) error {
+		object := new(T)
+		if len(event.Data.Object) > 0 {
+			if err := json.Unmarshal(event.Data.Object, object); err != nil {
+				return fmt.Errorf("failed to decode %s object: %w", event.Type, err)
+			}
+		}
+		return handler(ctx, event, object)
+	}
+}
+
+// HandleRequest verifies the signature of a webhook request, parses its event and dispatches it to
+// the registered handler. It responds 200 once handled, 400 when the request is unsigned, malformed or
+// of an unknown event type, and 500 when the handler fails so that Duffel retries the delivery.
+// The error is returned for logging.
+func (d *Dispatcher) HandleRequest(w http.ResponseWriter, r *http.Request) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return err
+	}
+
+	err = VerifyWebhookSignature(d.secret, r.Header.Get(WebhookSignatureHeader), body, d.clock.Now(), d.tolerance)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusBadRequest)
+		return err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	handler, ok := d.handlers[event.Type]
+	if !ok {
+		http.Error(w, "unknown event type", http.StatusBadRequest)
+		return fmt.Errorf("%w: %s", ErrUnknownWebhookEvent, event.Type)
+	}
+	if err := handler(r.Context(), &event); err != nil {
+		http.Error(w, "failed to handle event", http.StatusInternalServerError)
+		return fmt.Errorf("failed to handle webhook event %s: %w", event.ID, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// ServeHTTP implements http.Handler with HandleRequest, discarding its error.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = d.HandleRequest(w, r)
+}
+
+// VerifyWebhookSignature checks the X-Duffel-Signature header of a webhook: the v1 signature must be
+// the hex-encoded HMAC-SHA256 of "<t>.<body>" with the endpoint secret, and, when tolerance is not zero,
+// the timestamp t must be within tolerance of now.
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidWebhookSignature)
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidWebhookSignature)
+	}
+	if !hmac.Equal(expected, signWebhook(secret, timestamp, body)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidWebhookSignature)
+	}
+
+	if tolerance > 0 {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: malformed timestamp", ErrInvalidWebhookSignature)
+		}
+		if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidWebhookSignature)
+		}
+	}
+	return nil
+}
+
+func signWebhook(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,81 @@
+// Copyright 2021-present Airheart, Inc. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package duffel
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signedWebhookRequest(secret, body string, at time.Time) *http.Request {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/duffel", strings.NewReader(body))
+	req.Header.Set(
+		WebhookSignatureHeader,
+		fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(signWebhook(secret, timestamp, []byte(body)))),
+	)
+	return req
+}
+
+func TestDispatcherHandleRequest(t *testing.T) {
+	a := assert.New(t)
+
+	var received *Order
+	d := NewDispatcher("whsec_123")
+	HandleWebhook(
+		d, WebhookEventOrderCreated, func(_ context.Context, event *WebhookEvent, order *Order) error {
+			a.Equal("wev_123", event.ID)
+			received = order
+			return nil
+		},
+	)
+	HandleWebhook(
+		d, WebhookEventPing, func(context.Context, *WebhookEvent, *struct{}) error {
+			return fmt.Errorf("unavailable")
+		},
+	)
+
+	body := `{"id":"wev_123","type":"order.created","live_mode":false,"data":{"object":{"id":"ord_123"}}}`
+	w := httptest.NewRecorder()
+	a.NoError(d.HandleRequest(w, signedWebhookRequest("whsec_123", body, time.Now())))
+	a.Equal(http.StatusOK, w.Code)
+	if a.NotNil(received) {
+		a.Equal("ord_123", received.ID)
+	}
+
+	w = httptest.NewRecorder()
+	err := d.HandleRequest(w, signedWebhookRequest("whsec_other", body, time.Now()))
+	a.ErrorIs(err, ErrInvalidWebhookSignature)
+	a.Equal(http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	err = d.HandleRequest(w, signedWebhookRequest("whsec_123", body, time.Now().Add(-time.Hour)))
+	a.ErrorIs(err, ErrInvalidWebhookSignature)
+	a.Equal(http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	err = d.HandleRequest(w, signedWebhookRequest("whsec_123", `{"id":"wev_456","type":"order.unknown"}`, time.Now()))
+	a.ErrorIs(err, ErrUnknownWebhookEvent)
+	a.Equal(http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	err = d.HandleRequest(w, signedWebhookRequest("whsec_123", `{"id":"wev_789","type":"ping.triggered"}`, time.Now()))
+	a.ErrorContains(err, "unavailable")
+	a.Equal(http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/duffel", strings.NewReader(body))
+	a.ErrorIs(d.HandleRequest(w, req), ErrInvalidWebhookSignature)
+	a.Equal(http.StatusBadRequest, w.Code)
+}